@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ActionDescriptionBuilder builds an ActionDescription. Create it with NewAttack, NewCheck, NewLoadTest or NewOther.
+// Parameters get defaults based on their type and are ordered as they are added, Build validates the result.
+type ActionDescriptionBuilder struct {
+	description ActionDescription
+	nextOrder   int
+}
+
+// ParamOption configures a parameter added to an ActionDescriptionBuilder.
+type ParamOption func(*ActionParameter)
+
+// NewAttack starts an ActionDescription of kind attack using time control external.
+func NewAttack(id string) *ActionDescriptionBuilder {
+	return newActionDescriptionBuilder(id, Attack, TimeControlExternal)
+}
+
+// NewCheck starts an ActionDescription of kind check using time control internal.
+func NewCheck(id string) *ActionDescriptionBuilder {
+	return newActionDescriptionBuilder(id, Check, TimeControlInternal)
+}
+
+// NewLoadTest starts an ActionDescription of kind load test using time control internal.
+func NewLoadTest(id string) *ActionDescriptionBuilder {
+	return newActionDescriptionBuilder(id, LoadTest, TimeControlInternal)
+}
+
+// NewOther starts an ActionDescription of kind other using time control external.
+func NewOther(id string) *ActionDescriptionBuilder {
+	return newActionDescriptionBuilder(id, Other, TimeControlExternal)
+}
+
+func newActionDescriptionBuilder(id string, kind ActionKind, timeControl TimeControl) *ActionDescriptionBuilder {
+	return &ActionDescriptionBuilder{
+		description: ActionDescription{Id: id, Kind: kind, TimeControl: timeControl},
+		nextOrder:   1,
+	}
+}
+
+func (b *ActionDescriptionBuilder) Label(label string) *ActionDescriptionBuilder {
+	b.description.Label = label
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Description(description string) *ActionDescriptionBuilder {
+	b.description.Description = description
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Version(version string) *ActionDescriptionBuilder {
+	b.description.Version = version
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Icon(icon string) *ActionDescriptionBuilder {
+	b.description.Icon = new(icon)
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Category(category string) *ActionDescriptionBuilder {
+	b.description.Category = new(category)
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Technology(technology string) *ActionDescriptionBuilder {
+	b.description.Technology = new(technology)
+	return b
+}
+
+func (b *ActionDescriptionBuilder) TimeControl(timeControl TimeControl) *ActionDescriptionBuilder {
+	b.description.TimeControl = timeControl
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Hint(hint ActionHint) *ActionDescriptionBuilder {
+	b.description.Hint = &hint
+	return b
+}
+
+func (b *ActionDescriptionBuilder) AdditionalFlags(flags ...ActionDescriptionAdditionalFlags) *ActionDescriptionBuilder {
+	b.description.AdditionalFlags = &flags
+	return b
+}
+
+// TargetSelection sets the target selection and the target type of the description to the one of the selection.
+func (b *ActionDescriptionBuilder) TargetSelection(selection TargetSelection) *ActionDescriptionBuilder {
+	b.description.TargetSelection = &selection
+	b.description.TargetType = new(selection.TargetType)
+	return b
+}
+
+// StatusCallInterval sets the interval of the status calls, e.g. "1s".
+func (b *ActionDescriptionBuilder) StatusCallInterval(interval string) *ActionDescriptionBuilder {
+	b.description.Status = &MutatingEndpointReferenceWithCallInterval{CallInterval: new(interval)}
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Widgets(widgets ...Widget) *ActionDescriptionBuilder {
+	b.description.Widgets = &widgets
+	return b
+}
+
+func (b *ActionDescriptionBuilder) Metrics(metrics MetricsConfiguration) *ActionDescriptionBuilder {
+	b.description.Metrics = &metrics
+	return b
+}
+
+// Param adds the parameter. Unset fields are filled with the defaults of its type, parameters without order are ordered as they are added.
+func (b *ActionDescriptionBuilder) Param(parameter ActionParameter, opts ...ParamOption) *ActionDescriptionBuilder {
+	for _, opt := range opts {
+		opt(&parameter)
+	}
+	applyParameterDefaults(&parameter)
+	if parameter.Order == nil {
+		parameter.Order = new(b.nextOrder)
+	}
+	b.nextOrder = max(b.nextOrder, *parameter.Order+1)
+	b.description.Parameters = append(b.description.Parameters, parameter)
+	return b
+}
+
+// DurationParam adds a required parameter of type duration with a default value like "30s", entered in seconds, minutes or hours.
+func (b *ActionDescriptionBuilder) DurationParam(name, label, defaultValue string, opts ...ParamOption) *ActionDescriptionBuilder {
+	return b.Param(ActionParameter{Name: name, Label: label, Type: ActionParameterTypeDuration, DefaultValue: new(defaultValue)}, opts...)
+}
+
+// PercentageParam adds a required parameter of type percentage between 0 and 100.
+func (b *ActionDescriptionBuilder) PercentageParam(name, label string, defaultValue int, opts ...ParamOption) *ActionDescriptionBuilder {
+	return b.Param(ActionParameter{Name: name, Label: label, Type: ActionParameterTypePercentage, DefaultValue: new(strconv.Itoa(defaultValue))}, opts...)
+}
+
+func (b *ActionDescriptionBuilder) IntegerParam(name, label string, defaultValue int, opts ...ParamOption) *ActionDescriptionBuilder {
+	return b.Param(ActionParameter{Name: name, Label: label, Type: ActionParameterTypeInteger, DefaultValue: new(strconv.Itoa(defaultValue))}, opts...)
+}
+
+// StringParam adds a parameter of type string. An empty default value is omitted.
+func (b *ActionDescriptionBuilder) StringParam(name, label, defaultValue string, opts ...ParamOption) *ActionDescriptionBuilder {
+	parameter := ActionParameter{Name: name, Label: label, Type: ActionParameterTypeString}
+	if defaultValue != "" {
+		parameter.DefaultValue = new(defaultValue)
+	}
+	return b.Param(parameter, opts...)
+}
+
+func (b *ActionDescriptionBuilder) BooleanParam(name, label string, defaultValue bool, opts ...ParamOption) *ActionDescriptionBuilder {
+	return b.Param(ActionParameter{Name: name, Label: label, Type: ActionParameterTypeBoolean, DefaultValue: new(strconv.FormatBool(defaultValue))}, opts...)
+}
+
+func applyParameterDefaults(parameter *ActionParameter) {
+	switch parameter.Type {
+	case ActionParameterTypeDuration:
+		if parameter.Required == nil {
+			parameter.Required = new(true)
+		}
+		if parameter.DurationUnits == nil {
+			parameter.DurationUnits = new([]DurationUnit{DurationUnitSeconds, DurationUnitMinutes, DurationUnitHours})
+		}
+	case ActionParameterTypePercentage:
+		if parameter.Required == nil {
+			parameter.Required = new(true)
+		}
+		if parameter.MinValue == nil {
+			parameter.MinValue = new(0)
+		}
+		if parameter.MaxValue == nil {
+			parameter.MaxValue = new(100)
+		}
+	}
+}
+
+func ParamDescription(description string) ParamOption {
+	return func(p *ActionParameter) { p.Description = new(description) }
+}
+
+func ParamRequired(required bool) ParamOption {
+	return func(p *ActionParameter) { p.Required = new(required) }
+}
+
+func ParamAdvanced() ParamOption {
+	return func(p *ActionParameter) { p.Advanced = new(true) }
+}
+
+func ParamHint(hint ActionHint) ParamOption {
+	return func(p *ActionParameter) { p.Hint = &hint }
+}
+
+func ParamMinMax(minValue, maxValue int) ParamOption {
+	return func(p *ActionParameter) {
+		p.MinValue = new(minValue)
+		p.MaxValue = new(maxValue)
+	}
+}
+
+// ParamOptions sets the options of the parameter. Set optionsOnly to disallow other values, it is always set as the platform treats
+// a missing optionsOnly as true.
+func ParamOptions(optionsOnly bool, options ...ParameterOption) ParamOption {
+	return func(p *ActionParameter) {
+		p.Options = &options
+		p.OptionsOnly = new(optionsOnly)
+	}
+}
+
+func ParamDurationUnits(units ...DurationUnit) ParamOption {
+	return func(p *ActionParameter) { p.DurationUnits = &units }
+}
+
+// ParamOrder sets the order explicitly. Parameters added afterward without order continue after it.
+func ParamOrder(order int) ParamOption {
+	return func(p *ActionParameter) { p.Order = new(order) }
+}
+
+func ParamDeprecated(message string) ParamOption {
+	return func(p *ActionParameter) {
+		p.Deprecated = new(true)
+		p.DeprecationMessage = new(message)
+	}
+}
+
+// Build validates and returns the ActionDescription. Errors found by Lint are returned as well.
+func (b *ActionDescriptionBuilder) Build() (ActionDescription, error) {
+	var errs []error
+	if b.description.Id == "" {
+		errs = append(errs, errors.New("id is missing"))
+	}
+	if b.description.Label == "" {
+		errs = append(errs, errors.New("label is missing"))
+	}
+	if b.description.Version == "" {
+		errs = append(errs, errors.New("version is missing"))
+	}
+	if !b.description.TimeControl.Valid() {
+		errs = append(errs, fmt.Errorf("unknown time control %q", b.description.TimeControl))
+	}
+	if b.description.Status != nil && b.description.TimeControl == TimeControlInstantaneous {
+		errs = append(errs, errors.New("status call interval is set for time control instantaneous"))
+	}
+	for i, parameter := range b.description.Parameters {
+		if !parameter.Type.Valid() {
+			errs = append(errs, fmt.Errorf("parameters[%d].type: unknown type %q", i, parameter.Type))
+		}
+		if parameter.DurationUnits != nil && parameter.Type != ActionParameterTypeDuration {
+			errs = append(errs, fmt.Errorf("parameters[%d].durationUnits: only supported for type duration", i))
+		}
+	}
+	for _, finding := range Lint(b.description).Errors() {
+		errs = append(errs, fmt.Errorf("%s: %s", finding.Field, finding.Message))
+	}
+	if len(errs) > 0 {
+		return ActionDescription{}, fmt.Errorf("invalid action description %q: %w", b.description.Id, errors.Join(errs...))
+	}
+	return b.description, nil
+}
+
+// MustBuild is like Build but panics if the description is invalid. Use it in Describe.
+func (b *ActionDescriptionBuilder) MustBuild() ActionDescription {
+	description, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return description
+}
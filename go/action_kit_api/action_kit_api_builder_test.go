@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_produces_same_json_as_literal(t *testing.T) {
+	literal := ActionDescription{
+		Id:          "com.example.stress-cpu",
+		Label:       "Stress CPU",
+		Description: "Generates CPU load",
+		Version:     "1.0.0",
+		Icon:        new("data:image/svg+xml;base64,PHN2Zz48L3N2Zz4="),
+		Category:    new("resource"),
+		Kind:        Attack,
+		TimeControl: TimeControlExternal,
+		TargetType:  new("container"),
+		TargetSelection: &TargetSelection{
+			TargetType:          "container",
+			QuantityRestriction: new(QuantityRestrictionAll),
+		},
+		Parameters: []ActionParameter{
+			{Name: "duration", Label: "Duration", Type: ActionParameterTypeDuration, DefaultValue: new("30s"), Required: new(true), DurationUnits: new([]DurationUnit{DurationUnitSeconds, DurationUnitMinutes, DurationUnitHours}), Order: new(1)},
+			{Name: "cpuLoad", Label: "Load", Type: ActionParameterTypePercentage, DefaultValue: new("100"), Required: new(true), MinValue: new(0), MaxValue: new(100), Order: new(2)},
+			{Name: "workers", Label: "Workers", Type: ActionParameterTypeInteger, DefaultValue: new("0"), Advanced: new(true), Description: new("0 means one per core"), Order: new(3)},
+			{Name: "failOnOomKill", Label: "Fail on OOM kill", Type: ActionParameterTypeBoolean, DefaultValue: new("false"), Order: new(4)},
+		},
+	}
+
+	built, err := NewAttack("com.example.stress-cpu").
+		Label("Stress CPU").
+		Description("Generates CPU load").
+		Version("1.0.0").
+		Icon("data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=").
+		Category("resource").
+		TargetSelection(TargetSelection{TargetType: "container", QuantityRestriction: new(QuantityRestrictionAll)}).
+		DurationParam("duration", "Duration", "30s").
+		PercentageParam("cpuLoad", "Load", 100).
+		IntegerParam("workers", "Workers", 0, ParamAdvanced(), ParamDescription("0 means one per core")).
+		BooleanParam("failOnOomKill", "Fail on OOM kill", false).
+		Build()
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(literal)
+	require.NoError(t, err)
+	actual, err := json.Marshal(built)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestBuilder_order_continues_after_explicit_order(t *testing.T) {
+	description := NewCheck("check").Label("Check").Version("1.0.0").
+		DurationParam("duration", "Duration", "10s").
+		StringParam("url", "URL", "", ParamOrder(10)).
+		StringParam("method", "Method", "GET").
+		MustBuild()
+
+	assert.Equal(t, TimeControlInternal, description.TimeControl)
+	assert.Equal(t, 1, *description.Parameters[0].Order)
+	assert.Equal(t, 10, *description.Parameters[1].Order)
+	assert.Nil(t, description.Parameters[1].DefaultValue)
+	assert.Equal(t, 11, *description.Parameters[2].Order)
+}
+
+func TestBuilder_explicit_values_win_over_defaults(t *testing.T) {
+	description := NewAttack("attack").Label("Attack").Version("1.0.0").
+		PercentageParam("loss", "Loss", 50, ParamRequired(false), ParamMinMax(1, 90)).
+		MustBuild()
+
+	parameter := description.Parameters[0]
+	assert.False(t, *parameter.Required)
+	assert.Equal(t, 1, *parameter.MinValue)
+	assert.Equal(t, 90, *parameter.MaxValue)
+}
+
+func TestBuilder_ParamOptions(t *testing.T) {
+	option := ExplicitParameterOption{Label: "Fast", Value: "fast"}
+	description := NewAttack("attack").Label("Attack").Version("1.0.0").
+		StringParam("mode", "Mode", "fast", ParamOptions(true, option)).
+		StringParam("name", "Name", "fast", ParamOptions(false, option)).
+		MustBuild()
+
+	assert.True(t, *description.Parameters[0].OptionsOnly)
+	assert.False(t, *description.Parameters[1].OptionsOnly, "free text must be set explicitly")
+}
+
+func TestBuilder_validation(t *testing.T) {
+	_, err := NewOther("").
+		TimeControl(TimeControlInstantaneous).
+		StatusCallInterval("1s").
+		StringParam("name", "Name", "", ParamDurationUnits(DurationUnitSeconds)).
+		StringParam("name", "Name again", "").
+		Build()
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "id is missing")
+	assert.ErrorContains(t, err, "label is missing")
+	assert.ErrorContains(t, err, "version is missing")
+	assert.ErrorContains(t, err, "status call interval is set for time control instantaneous")
+	assert.ErrorContains(t, err, "parameters[0].durationUnits: only supported for type duration")
+	assert.ErrorContains(t, err, `parameters[1].name: duplicate parameter name "name"`)
+}
+
+func TestBuilder_MustBuild_panics(t *testing.T) {
+	assert.Panics(t, func() { NewAttack("attack").MustBuild() })
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type LintSeverity string
+
+const (
+	// LintSeverityError marks a mistake which breaks the action in the platform.
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityWarning marks a likely mistake.
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintFinding is a single problem found in an ActionDescription.
+type LintFinding struct {
+	Severity LintSeverity
+	// Field is the path of the offending field within the description, e.g. "parameters[2].defaultValue".
+	Field   string
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Field, f.Message)
+}
+
+type LintFindings []LintFinding
+
+// Errors returns the findings with severity LintSeverityError.
+func (f LintFindings) Errors() LintFindings {
+	return f.withSeverity(LintSeverityError)
+}
+
+// Warnings returns the findings with severity LintSeverityWarning.
+func (f LintFindings) Warnings() LintFindings {
+	return f.withSeverity(LintSeverityWarning)
+}
+
+func (f LintFindings) withSeverity(severity LintSeverity) LintFindings {
+	var result LintFindings
+	for _, finding := range f {
+		if finding.Severity == severity {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
+type lintOptions struct {
+	emittedMetrics []string
+}
+
+type LintOption func(*lintOptions)
+
+// WithEmittedMetrics declares the names of the metrics emitted by the action.
+// If given, line chart widgets referring to other metric names are reported.
+func WithEmittedMetrics(names ...string) LintOption {
+	return func(o *lintOptions) {
+		o.emittedMetrics = append(o.emittedMetrics, names...)
+	}
+}
+
+// Lint checks an ActionDescription for mistakes which would otherwise only show up in the platform UI.
+func Lint(description ActionDescription, opts ...LintOption) LintFindings {
+	options := lintOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var findings LintFindings
+	findings = append(findings, lintParameters("parameters", description.Parameters)...)
+	findings = append(findings, lintTargetSelection(description)...)
+	findings = append(findings, lintWidgets(description, options)...)
+	if description.Metrics != nil && description.Metrics.Query != nil {
+		findings = append(findings, lintParameters("metrics.query.parameters", description.Metrics.Query.Parameters)...)
+	}
+	return findings
+}
+
+func lintParameters(path string, parameters []ActionParameter) LintFindings {
+	var findings LintFindings
+	names := make(map[string]int)
+	orders := make(map[int]int)
+	for i, parameter := range parameters {
+		field := fmt.Sprintf("%s[%d]", path, i)
+		carriesValue := parameter.Type != ActionParameterTypeSeparator && parameter.Type != ActionParameterTypeHeader && parameter.Type != ActionParameterTypeTargetSelection
+
+		if parameter.Name == "" {
+			findings = append(findings, LintFinding{LintSeverityError, field + ".name", "name is missing"})
+		} else if carriesValue {
+			if previous, ok := names[parameter.Name]; ok {
+				findings = append(findings, LintFinding{LintSeverityError, field + ".name", fmt.Sprintf("duplicate parameter name %q, already used by %s[%d]", parameter.Name, path, previous)})
+			} else {
+				names[parameter.Name] = i
+			}
+		}
+
+		if parameter.Label == "" && parameter.Type != ActionParameterTypeSeparator && parameter.Type != ActionParameterTypeTargetSelection {
+			findings = append(findings, LintFinding{LintSeverityError, field + ".label", "label is missing"})
+		}
+
+		if parameter.Order != nil {
+			if previous, ok := orders[*parameter.Order]; ok {
+				findings = append(findings, LintFinding{LintSeverityWarning, field + ".order", fmt.Sprintf("order %d is already used by %s[%d]", *parameter.Order, path, previous)})
+			} else {
+				orders[*parameter.Order] = i
+			}
+		}
+
+		if parameter.MinValue != nil && parameter.MaxValue != nil && *parameter.MinValue > *parameter.MaxValue {
+			findings = append(findings, LintFinding{LintSeverityError, field + ".minValue", fmt.Sprintf("minValue %d is greater than maxValue %d", *parameter.MinValue, *parameter.MaxValue)})
+		}
+
+		if finding := lintDefaultValueInOptions(field, parameter); finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+	return findings
+}
+
+func lintDefaultValueInOptions(field string, parameter ActionParameter) *LintFinding {
+	if parameter.DefaultValue == nil || *parameter.DefaultValue == "" || parameter.Options == nil || len(*parameter.Options) == 0 {
+		return nil
+	}
+	var allowed []string
+	for _, option := range *parameter.Options {
+		switch o := option.(type) {
+		case ExplicitParameterOption:
+			allowed = append(allowed, o.Value)
+		case *ExplicitParameterOption:
+			allowed = append(allowed, o.Value)
+		default:
+			// options from target attributes are only known to the platform
+			return nil
+		}
+	}
+
+	defaults := []string{*parameter.DefaultValue}
+	if parameter.Type == ActionParameterTypeStringArray || parameter.Type == ActionParameterTypeString1 {
+		var values []string
+		if err := json.Unmarshal([]byte(*parameter.DefaultValue), &values); err == nil {
+			defaults = values
+		}
+	}
+
+	// optionsOnly defaults to true, free text is only allowed if it is set to false explicitly
+	severity := LintSeverityError
+	if parameter.OptionsOnly != nil && !*parameter.OptionsOnly {
+		severity = LintSeverityWarning
+	}
+	for _, value := range defaults {
+		if !slices.Contains(allowed, value) {
+			return &LintFinding{severity, field + ".defaultValue", fmt.Sprintf("default value %q is not one of the options [%s]", value, strings.Join(allowed, ", "))}
+		}
+	}
+	return nil
+}
+
+func lintTargetSelection(description ActionDescription) LintFindings {
+	if description.TargetSelection == nil {
+		return nil
+	}
+	if description.TargetType == nil {
+		return LintFindings{{LintSeverityError, "targetType", fmt.Sprintf("targetType is missing but targetSelection refers to %q", description.TargetSelection.TargetType)}}
+	}
+	if description.TargetSelection.TargetType != *description.TargetType {
+		return LintFindings{{LintSeverityError, "targetSelection.targetType", fmt.Sprintf("%q does not match targetType %q", description.TargetSelection.TargetType, *description.TargetType)}}
+	}
+	return nil
+}
+
+func lintWidgets(description ActionDescription, options lintOptions) LintFindings {
+	if description.Widgets == nil {
+		return nil
+	}
+	var findings LintFindings
+	for i, widget := range *description.Widgets {
+		var lineChart *LineChartWidget
+		switch w := widget.(type) {
+		case LineChartWidget:
+			lineChart = &w
+		case *LineChartWidget:
+			lineChart = w
+		}
+		if lineChart == nil {
+			continue
+		}
+		field := fmt.Sprintf("widgets[%d].identity.metricName", i)
+		if lineChart.Identity.MetricName == "" {
+			findings = append(findings, LintFinding{LintSeverityError, field, "metricName is missing"})
+		} else if len(options.emittedMetrics) > 0 && !slices.Contains(options.emittedMetrics, lineChart.Identity.MetricName) {
+			findings = append(findings, LintFinding{LintSeverityWarning, field, fmt.Sprintf("metric %q is never emitted by the action", lineChart.Identity.MetricName)})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validDescription() ActionDescription {
+	return ActionDescription{
+		Id:         "com.example.action",
+		Label:      "Example",
+		TargetType: new("container"),
+		TargetSelection: &TargetSelection{
+			TargetType: "container",
+		},
+		Parameters: []ActionParameter{
+			{Name: "-", Type: ActionParameterTypeTargetSelection},
+			{Name: "duration", Label: "Duration", Type: ActionParameterTypeDuration, Order: new(0)},
+			{Name: "mode", Label: "Mode", Type: ActionParameterTypeString, Order: new(1), DefaultValue: new("fast"), OptionsOnly: new(true), Options: new([]ParameterOption{
+				ExplicitParameterOption{Label: "Fast", Value: "fast"},
+			})},
+			{Name: "containers", Label: "Containers", Type: ActionParameterTypeStringArray, DefaultValue: new(`["a"]`), Options: new([]ParameterOption{
+				ParameterOptionsFromTargetAttribute{Attribute: "container.name"},
+			})},
+		},
+		Widgets: new([]Widget{
+			LineChartWidget{Type: ComSteadybitWidgetLineChart, Identity: LineChartWidgetIdentityConfig{MetricName: "latency"}},
+		}),
+		Metrics: &MetricsConfiguration{
+			Query: &MetricsQueryConfiguration{
+				Parameters: []ActionParameter{{Name: "query", Label: "Query", Type: ActionParameterTypeString}},
+			},
+		},
+	}
+}
+
+func TestLint_valid_description(t *testing.T) {
+	assert.Empty(t, Lint(validDescription(), WithEmittedMetrics("latency")))
+}
+
+func TestLint_findings(t *testing.T) {
+	description := validDescription()
+	description.TargetSelection.TargetType = "host"
+	description.Parameters = append(description.Parameters,
+		ActionParameter{Name: "duration", Label: "Duration again", Type: ActionParameterTypeDuration, Order: new(1)},
+		ActionParameter{Name: "colors", Label: "Colors", Type: ActionParameterTypeStringArray, DefaultValue: new(`["red","blue"]`), OptionsOnly: new(true), Options: new([]ParameterOption{
+			ExplicitParameterOption{Label: "Red", Value: "red"},
+		})},
+		ActionParameter{Name: "workers", Label: "Workers", Type: ActionParameterTypeInteger, MinValue: new(10), MaxValue: new(1)},
+	)
+	description.Widgets = new([]Widget{
+		&LineChartWidget{Identity: LineChartWidgetIdentityConfig{MetricName: "throughput"}},
+		LineChartWidget{},
+	})
+	description.Metrics.Query.Parameters = []ActionParameter{{Name: "query", Type: ActionParameterTypeString}}
+
+	findings := Lint(description, WithEmittedMetrics("latency"))
+
+	assert.Equal(t, LintFindings{
+		{LintSeverityError, "parameters[4].name", `duplicate parameter name "duration", already used by parameters[1]`},
+		{LintSeverityWarning, "parameters[4].order", "order 1 is already used by parameters[2]"},
+		{LintSeverityError, "parameters[5].defaultValue", `default value "blue" is not one of the options [red]`},
+		{LintSeverityError, "parameters[6].minValue", "minValue 10 is greater than maxValue 1"},
+		{LintSeverityError, "targetSelection.targetType", `"host" does not match targetType "container"`},
+		{LintSeverityWarning, "widgets[0].identity.metricName", `metric "throughput" is never emitted by the action`},
+		{LintSeverityError, "widgets[1].identity.metricName", "metricName is missing"},
+		{LintSeverityError, "metrics.query.parameters[0].label", "label is missing"},
+	}, findings)
+	assert.Len(t, findings.Errors(), 6)
+	assert.Len(t, findings.Warnings(), 2)
+}
+
+func TestLint_default_value_not_in_options(t *testing.T) {
+	options := new([]ParameterOption{ExplicitParameterOption{Label: "Red", Value: "red"}})
+	description := validDescription()
+	description.Parameters = append(description.Parameters,
+		ActionParameter{Name: "color", Label: "Color", Type: ActionParameterTypeString, DefaultValue: new("blue"), Options: options},
+		ActionParameter{Name: "shade", Label: "Shade", Type: ActionParameterTypeString, DefaultValue: new("blue"), OptionsOnly: new(false), Options: options},
+	)
+
+	assert.Equal(t, LintFindings{
+		{LintSeverityError, "parameters[4].defaultValue", `default value "blue" is not one of the options [red]`},
+		{LintSeverityWarning, "parameters[5].defaultValue", `default value "blue" is not one of the options [red]`},
+	}, Lint(description, WithEmittedMetrics("latency")))
+}
+
+func TestLint_missing_target_type(t *testing.T) {
+	description := validDescription()
+	description.TargetType = nil
+	assert.Equal(t, LintFindings{
+		{LintSeverityError, "targetType", `targetType is missing but targetSelection refers to "container"`},
+	}, Lint(description))
+}
@@ -11,7 +11,7 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
 )
 
 type iface struct {
@@ -48,7 +48,7 @@ func (i *iface) hasFlag(f string) bool {
 func ListNonLoopbackInterfaceNames(ctx context.Context, r CommandRunner) ([]string, error) {
 	ifcs, err := listInterfaces(ctx, r)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to list interfaces")
+		utils.Logger(ctx).Error().Err(err).Msg("failed to list interfaces")
 		return nil, err
 	}
 
@@ -73,7 +73,7 @@ func HasIstioRedirect(ctx context.Context, r CommandRunner) (bool, error) {
 
 	legacyOut, legacyErr := r.run(ctx, []string{"iptables-legacy-save", "-t", "nat"}, nil)
 	if legacyErr != nil {
-		log.Debug().Err(legacyErr).Msg("iptables-legacy-save not available, skipping legacy check")
+		utils.Logger(ctx).Debug().Err(legacyErr).Msg("iptables-legacy-save not available, skipping legacy check")
 		return false, nil
 	}
 
@@ -95,7 +95,7 @@ func HasCiliumIpRoutes(ctx context.Context, r CommandRunner) (bool, error) {
 		return false, fmt.Errorf("failed to unmarshal rules: %w", err)
 	}
 
-	log.Trace().Interface("rules", rules).Msg("listed routes")
+	utils.Logger(ctx).Trace().Interface("rules", rules).Msg("listed routes")
 
 	for _, rule := range rules {
 		if strings.HasPrefix(rule.Dev, "cilium_") {
@@ -123,6 +123,6 @@ func listInterfaces(ctx context.Context, r CommandRunner) ([]iface, error) {
 		}
 	}
 
-	log.Trace().Interface("interfaces", interfaces).Msg("listed network interfaces")
+	utils.Logger(ctx).Trace().Interface("interfaces", interfaces).Msg("listed network interfaces")
 	return validInterfaces, nil
 }
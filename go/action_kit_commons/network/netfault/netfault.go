@@ -55,6 +55,10 @@ type CommandRunner interface {
 // the action's per-execution state (action_kit_sdk JSON state) and pass it
 // back to Revert.
 //
+// Includes overlapping the RestrictedEndpoints of the filter are excluded
+// or rejected before, depending on the RestrictedEndpointsMode. The excludes
+// are added to a copy, the passed opts are not changed.
+//
 // The returned snapshot is empty (QdiscSnapshot.IsEmpty() == true) when:
 //   - strict-root-qdisc mode is on (preflight refused non-`noqueue` roots —
 //     there's nothing to preserve),
@@ -66,6 +70,10 @@ type CommandRunner interface {
 // replaced, and replaying it from Revert would clobber a partial attack
 // install with the original tree. Drop it instead.
 func Apply(ctx context.Context, runner CommandRunner, opts Opts) (QdiscSnapshot, error) {
+	opts, err := protectRestrictedEndpoints(ctx, opts, modeAdd)
+	if err != nil {
+		return QdiscSnapshot{}, err
+	}
 	return generateAndRunCommands(ctx, runner, opts, modeAdd, QdiscSnapshot{})
 }
 
@@ -109,7 +117,7 @@ func PreflightCheck(ctx context.Context, runner CommandRunner, opts Opts) error
 
 	kinds, err := inspectRootQdiscs(ctx, runner)
 	if err != nil {
-		log.Warn().Err(err).Msg("failed to inspect root qdiscs; skipping preflight check")
+		utils.Logger(ctx).Warn().Err(err).Msg("failed to inspect root qdiscs; skipping preflight check")
 		return nil
 	}
 	for _, ifc := range interfaces {
@@ -137,7 +145,11 @@ func hasActiveNetfault(netNsId string) bool {
 // by callers that don't care about preserving the pre-attack tree, e.g.
 // iptables-only attacks).
 func Revert(ctx context.Context, runner CommandRunner, opts Opts, snap QdiscSnapshot) error {
-	_, err := generateAndRunCommands(ctx, runner, opts, modeDelete, snap)
+	opts, err := protectRestrictedEndpoints(ctx, opts, modeDelete)
+	if err != nil {
+		return err
+	}
+	_, err = generateAndRunCommands(ctx, runner, opts, modeDelete, snap)
 	return err
 }
 
@@ -185,7 +197,7 @@ func generateAndRunCommands(ctx context.Context, runner CommandRunner, opts Opts
 	// the host with the kernel's default-restore path than with a stale
 	// snapshot we can't trust.
 	if mode == modeAdd && err != nil && !snapshot.IsEmpty() {
-		log.Warn().Str("netNs", netNsID).Msg("dropped qdisc snapshot because apply errored; revert will fall back to kernel-default restore")
+		utils.Logger(ctx).Warn().Str("netNs", netNsID).Msg("dropped qdisc snapshot because apply errored; revert will fall back to kernel-default restore")
 		snapshot = QdiscSnapshot{}
 	}
 
@@ -197,7 +209,7 @@ func generateAndRunCommands(ctx context.Context, runner CommandRunner, opts Opts
 		// should replay a snapshot when strict mode is on at revert time.
 		if !strictRootQdisc && !incoming.IsEmpty() {
 			if rerr := applyRestore(runner, incoming); rerr != nil {
-				log.Warn().Err(rerr).Str("netNs", netNsID).Msg("qdisc restore failed")
+				utils.Logger(ctx).Warn().Err(rerr).Str("netNs", netNsID).Msg("qdisc restore failed")
 				err = errors.Join(err, rerr)
 			}
 		}
@@ -302,13 +314,13 @@ func runIptablesScripts(ctx context.Context, runner CommandRunner, opts Opts, mo
 	logIptablesScripts(mode, v4, v6)
 	if len(v4) > 0 {
 		if _, restoreErr := runner.run(ctx, []string{"iptables-restore", "-w", "-n"}, v4); restoreErr != nil {
-			log.Warn().Err(restoreErr).Str("mode", string(mode)).Msg("iptables-restore failed")
+			utils.Logger(ctx).Warn().Err(restoreErr).Str("mode", string(mode)).Msg("iptables-restore failed")
 			*outErr = errors.Join(*outErr, restoreErr)
 		}
 	}
 	if ipv6Supported() && len(v6) > 0 {
 		if _, restoreErr := runner.run(ctx, []string{"ip6tables-restore", "-w", "-n"}, v6); restoreErr != nil {
-			log.Warn().Err(restoreErr).Str("mode", string(mode)).Msg("ip6tables-restore failed")
+			utils.Logger(ctx).Warn().Err(restoreErr).Str("mode", string(mode)).Msg("ip6tables-restore failed")
 			*outErr = errors.Join(*outErr, restoreErr)
 		}
 	}
@@ -443,16 +455,16 @@ func snapshotInterfaceNames(snap QdiscSnapshot) []string {
 }
 
 func logCurrentIpRules(ctx context.Context, runner CommandRunner, family family, when string) {
-	if !log.Trace().Enabled() {
+	if !utils.Logger(ctx).Trace().Enabled() {
 		return
 	}
 
 	stdout, err := executeIpCommands(ctx, runner, []string{"rule show"}, ipFamilyFlag, string(family))
 	if err != nil {
-		log.Trace().Err(err).Msg("failed to get current ip rules")
+		utils.Logger(ctx).Trace().Err(err).Msg("failed to get current ip rules")
 		return
 	} else {
-		log.Trace().Str("family", string(family)).Str("when", when).Str("rules", stdout).Msg("current ip rules")
+		utils.Logger(ctx).Trace().Str("family", string(family)).Str("when", when).Str("rules", stdout).Msg("current ip rules")
 	}
 }
 
@@ -518,24 +530,24 @@ func executeIpCommands(ctx context.Context, runner CommandRunner, cmds []string,
 }
 
 func logCurrentTcRules(ctx context.Context, runner CommandRunner, s string) {
-	if !log.Trace().Enabled() {
+	if !utils.Logger(ctx).Trace().Enabled() {
 		return
 	}
 
 	stdout, err := executeTcCommands(ctx, runner, []string{"qdisc show"})
 	if err != nil {
-		log.Trace().Err(err).Msg("failed to get current tc rules")
+		utils.Logger(ctx).Trace().Err(err).Msg("failed to get current tc rules")
 		return
 	} else {
-		log.Trace().Str("when", s).Str("rules", stdout).Msg("current tc qdisc")
+		utils.Logger(ctx).Trace().Str("when", s).Str("rules", stdout).Msg("current tc qdisc")
 	}
 
 	stdout, err = executeTcCommands(ctx, runner, []string{"filter show"})
 	if err != nil {
-		log.Trace().Err(err).Msg("failed to get current tc rules")
+		utils.Logger(ctx).Trace().Err(err).Msg("failed to get current tc rules")
 		return
 	} else {
-		log.Trace().Str("when", s).Str("rules", stdout).Msg("current tc filter")
+		utils.Logger(ctx).Trace().Str("when", s).Str("rules", stdout).Msg("current tc filter")
 	}
 }
 
@@ -10,10 +10,51 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestApply_protects_restricted_endpoints(t *testing.T) {
+	ipv6Supported = func() bool { return false }
+	defer func() { ipv6Supported = defaultIpv6Supported }()
+	endpoints := []action_kit_api.RestrictedEndpoint{{Name: "platform", Cidr: "10.0.0.1/32", PortMin: 443, PortMax: 443}}
+
+	rejected := &BlackholeOpts{Filter: Filter{
+		Include:                 []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "*")},
+		RestrictedEndpoints:     endpoints,
+		RestrictedEndpointsMode: RestrictedEndpointsReject,
+	}}
+	r := &fakeRunner{netNsId: "ns-restricted"}
+	_, err := Apply(context.Background(), r, rejected)
+	var restricted *ErrRestrictedEndpoint
+	assert.ErrorAs(t, err, &restricted)
+	assert.Empty(t, r.calls, "nothing is applied for rejected attacks")
+
+	excluded := &BlackholeOpts{Filter: Filter{
+		Include:             []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "*")},
+		RestrictedEndpoints: endpoints,
+	}}
+	_, err = Apply(context.Background(), r, excluded)
+	assert.NoError(t, err)
+	var cmds []string
+	for _, call := range r.calls {
+		cmds = append(cmds, call.cmds...)
+	}
+	assert.Contains(t, strings.Join(cmds, "\n"), "10.0.0.1/32", "the restricted endpoint is excluded")
+	assert.Empty(t, excluded.Exclude, "the opts of the caller are not changed")
+	assert.NoError(t, Revert(context.Background(), r, excluded, QdiscSnapshot{}))
+	assert.Empty(t, excluded.Exclude)
+	assert.False(t, hasActiveNetfault(r.id()), "revert removes the attack applied with the excludes")
+
+	reverted := &BlackholeOpts{Filter: Filter{
+		Include:                 []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "*")},
+		RestrictedEndpoints:     endpoints,
+		RestrictedEndpointsMode: RestrictedEndpointsReject,
+	}}
+	assert.NoError(t, Revert(context.Background(), r, reverted, QdiscSnapshot{}), "revert is never rejected")
+}
+
 func TestApply_Order_IptablesBeforeTcWhenTcpPshOnly(t *testing.T) {
 	// Disable ipv6 for the test to avoid ip6tables invocation
 	ipv6Supported = func() bool { return false }
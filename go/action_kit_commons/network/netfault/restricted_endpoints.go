@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package netfault
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
+)
+
+// RestrictedEndpointsMode defines how includes overlapping restricted endpoints are handled.
+type RestrictedEndpointsMode string
+
+const (
+	// RestrictedEndpointsExclude adds excludes for the overlapping restricted endpoints.
+	RestrictedEndpointsExclude RestrictedEndpointsMode = "exclude"
+	// RestrictedEndpointsReject fails if an include overlaps a restricted endpoint.
+	RestrictedEndpointsReject RestrictedEndpointsMode = "reject"
+)
+
+// ErrRestrictedEndpoint is returned by ProtectRestrictedEndpoints in RestrictedEndpointsReject mode.
+type ErrRestrictedEndpoint struct {
+	Endpoints []network.NetWithPortRange
+}
+
+func (e *ErrRestrictedEndpoint) Error() string {
+	endpoints := make([]string, 0, len(e.Endpoints))
+	for _, endpoint := range e.Endpoints {
+		endpoints = append(endpoints, endpoint.String())
+	}
+	return fmt.Sprintf("the attack would affect restricted endpoints: %s", strings.Join(endpoints, ", "))
+}
+
+// ProtectRestrictedEndpoints makes sure the filter does not affect the restricted endpoints sent in the execution context.
+// Restricted endpoints overlapping an include are either added as exclude or rejected, depending on the mode. Endpoints which are already excluded
+// are skipped, so that it can be called in prepare for the messages and again by Apply. Restricted endpoints with an invalid CIDR are rejected in
+// RestrictedEndpointsReject mode and ignored otherwise.
+// The returned messages list the added excludes and are meant to be reported in the prepare result.
+func (f *Filter) ProtectRestrictedEndpoints(endpoints []action_kit_api.RestrictedEndpoint, mode RestrictedEndpointsMode) ([]action_kit_api.Message, error) {
+	excludes, err := network.RestrictedEndpointsToExcludes(endpoints)
+	if err != nil {
+		if mode == RestrictedEndpointsReject {
+			return nil, err
+		}
+		log.Warn().Err(err).Msg("Ignoring restricted endpoints with invalid cidr")
+	}
+	overlapping := slices.DeleteFunc(necessaryExcludes(excludes, f.Include), func(exclude network.NetWithPortRange) bool {
+		return slices.ContainsFunc(f.Exclude, func(existing network.NetWithPortRange) bool {
+			return existing.String() == exclude.String()
+		})
+	})
+	if len(overlapping) == 0 {
+		return nil, nil
+	}
+	if mode == RestrictedEndpointsReject {
+		return nil, &ErrRestrictedEndpoint{Endpoints: overlapping}
+	}
+
+	f.Exclude = append(f.Exclude, overlapping...)
+	messages := make([]action_kit_api.Message, 0, len(overlapping))
+	for _, exclude := range overlapping {
+		messages = append(messages, action_kit_api.Message{
+			Level:   new(action_kit_api.Info),
+			Message: fmt.Sprintf("Excluded restricted endpoint from the attack: %s", exclude.String()),
+		})
+	}
+	return messages, nil
+}
+
+// filterProvider is implemented by all attacks embedding a Filter.
+type filterProvider interface {
+	filter() *Filter
+}
+
+func (f *Filter) filter() *Filter {
+	return f
+}
+
+// protectRestrictedEndpoints applies the restricted endpoints of the filter of the attack before its commands are generated.
+// The excludes are added to a copy of the opts, so that the caller's opts are not changed and repeated calls don't stack them.
+// On delete, the excludes are always added as Apply did, so that the same rules are removed.
+func protectRestrictedEndpoints(ctx context.Context, opts Opts, mode mode) (Opts, error) {
+	p, ok := opts.(filterProvider)
+	if !ok || len(p.filter().RestrictedEndpoints) == 0 {
+		return opts, nil
+	}
+	// only pointers to the attack opts embedding the Filter implement filterProvider
+	copied := reflect.New(reflect.TypeOf(opts).Elem())
+	copied.Elem().Set(reflect.ValueOf(opts).Elem())
+	protected := copied.Interface().(Opts)
+
+	f := protected.(filterProvider).filter()
+	f.Exclude = slices.Clone(f.Exclude)
+	endpointsMode := f.RestrictedEndpointsMode
+	if mode == modeDelete {
+		endpointsMode = RestrictedEndpointsExclude
+	}
+	messages, err := f.ProtectRestrictedEndpoints(f.RestrictedEndpoints, endpointsMode)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		utils.Logger(ctx).Info().Msg(message.Message)
+	}
+	return protected, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package netfault
+
+import (
+	"testing"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var restrictedEndpoints = []action_kit_api.RestrictedEndpoint{
+	{Name: "platform", Cidr: "10.0.0.1/32", PortMin: 443, PortMax: 443},
+	{Name: "unrelated", Cidr: "192.168.0.1/32"},
+}
+
+func TestFilter_ProtectRestrictedEndpoints_excludes_overlapping_endpoints(t *testing.T) {
+	filter := Filter{Include: []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "*")}}
+
+	messages, err := filter.ProtectRestrictedEndpoints(restrictedEndpoints, RestrictedEndpointsExclude)
+
+	require.NoError(t, err)
+	require.Len(t, filter.Exclude, 1)
+	assert.Equal(t, "10.0.0.1/32 443 # platform", filter.Exclude[0].String())
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Excluded restricted endpoint from the attack: 10.0.0.1/32 443 # platform", messages[0].Message)
+	assert.Equal(t, action_kit_api.Info, *messages[0].Level)
+}
+
+func TestFilter_ProtectRestrictedEndpoints_rejects_overlapping_endpoints(t *testing.T) {
+	filter := Filter{Include: []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "443")}}
+
+	_, err := filter.ProtectRestrictedEndpoints(restrictedEndpoints, RestrictedEndpointsReject)
+
+	assert.EqualError(t, err, "the attack would affect restricted endpoints: 10.0.0.1/32 443 # platform")
+	assert.Empty(t, filter.Exclude)
+}
+
+func TestFilter_ProtectRestrictedEndpoints_ignores_non_overlapping_endpoints(t *testing.T) {
+	filter := Filter{Include: []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "80")}}
+
+	messages, err := filter.ProtectRestrictedEndpoints(restrictedEndpoints, RestrictedEndpointsReject)
+
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+	assert.Empty(t, filter.Exclude)
+}
+
+func TestFilter_ProtectRestrictedEndpoints_is_idempotent(t *testing.T) {
+	filter := Filter{Include: []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "*")}}
+
+	_, err := filter.ProtectRestrictedEndpoints(restrictedEndpoints, RestrictedEndpointsExclude)
+	require.NoError(t, err)
+	messages, err := filter.ProtectRestrictedEndpoints(restrictedEndpoints, RestrictedEndpointsReject)
+
+	require.NoError(t, err, "already excluded endpoints are not rejected")
+	assert.Empty(t, messages)
+	assert.Len(t, filter.Exclude, 1)
+}
+
+func TestFilter_ProtectRestrictedEndpoints_invalid_cidr(t *testing.T) {
+	endpoints := append([]action_kit_api.RestrictedEndpoint{{Name: "invalid", Cidr: "not-a-cidr"}}, restrictedEndpoints...)
+
+	filter := Filter{Include: []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "*")}}
+	_, err := filter.ProtectRestrictedEndpoints(endpoints, RestrictedEndpointsReject)
+	assert.ErrorContains(t, err, `restricted endpoint "invalid" has an invalid cidr`)
+	assert.Empty(t, filter.Exclude)
+
+	_, err = filter.ProtectRestrictedEndpoints(endpoints, RestrictedEndpointsExclude)
+	assert.NoError(t, err)
+	assert.Len(t, filter.Exclude, 1, "the valid endpoints are excluded")
+}
+
+func TestFilter_ProtectRestrictedEndpoints_skips_endpoints_without_cidr(t *testing.T) {
+	endpoints := append([]action_kit_api.RestrictedEndpoint{{Name: "platform", Url: "https://platform.steadybit.com"}}, restrictedEndpoints...)
+
+	filter := Filter{Include: []network.NetWithPortRange{mustParseNetWithPortRange("10.0.0.0/8", "80")}}
+	messages, err := filter.ProtectRestrictedEndpoints(endpoints, RestrictedEndpointsReject)
+
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+}
@@ -8,7 +8,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
 )
 
@@ -20,7 +19,7 @@ type processRunner struct {
 }
 
 func (p processRunner) run(ctx context.Context, args []string, cmds []string) (string, error) {
-	log.Info().Strs("cmds", cmds).Strs("args", args[1:]).Str("path", args[0]).Msg("running commands")
+	utils.Logger(ctx).Info().Strs("cmds", cmds).Strs("args", args[1:]).Str("path", args[0]).Msg("running commands")
 
 	var outb, errb bytes.Buffer
 	cmd := utils.RootCommandContext(ctx, args[0], args[1:]...)
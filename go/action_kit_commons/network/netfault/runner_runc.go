@@ -82,7 +82,7 @@ func (r *runcRunner) executeInNamedNetworkUsingIpNetNs(ctx context.Context, proc
 		}
 	}
 
-	log.Info().Str("netns", netns).Strs("cmds", cmds).Strs("processArgs", processArgs).Msg("running commands in network namespace using ip netns")
+	utils.Logger(ctx).Info().Str("netns", netns).Strs("cmds", cmds).Strs("processArgs", processArgs).Msg("running commands in network namespace using ip netns")
 
 	ipArgs := append([]string{"netns", "exec", netns}, processArgs...)
 	var outb, errb bytes.Buffer
@@ -105,7 +105,7 @@ func (r *runcRunner) executeInNamedNetworkUsingIpNetNs(ctx context.Context, proc
 }
 
 func (r *runcRunner) executeInNetworkNamespaceUsingRunc(ctx context.Context, processArgs []string, cmds []string) (string, error) {
-	log.Trace().Strs("cmds", cmds).Strs("processArgs", processArgs).Msg("running commands in network namespace using runc")
+	utils.Logger(ctx).Trace().Strs("cmds", cmds).Strs("processArgs", processArgs).Msg("running commands in network namespace using runc")
 
 	id := nextContainerId(path.Base(processArgs[0]), r.sidecar.Id)
 	bundle, err := r.runc.Create(ctx, "/", id)
@@ -114,7 +114,7 @@ func (r *runcRunner) executeInNetworkNamespaceUsingRunc(ctx context.Context, pro
 	}
 	defer func() {
 		if err := bundle.Remove(); err != nil {
-			log.Warn().Str("id", id).Err(err).Msg("failed to remove bundle")
+			utils.Logger(ctx).Warn().Str("id", id).Err(err).Msg("failed to remove bundle")
 		}
 	}()
 
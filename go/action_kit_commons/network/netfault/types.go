@@ -3,7 +3,10 @@
 
 package netfault
 
-import "github.com/steadybit/action-kit/go/action_kit_commons/network"
+import (
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+)
 
 type mode string
 type family string
@@ -18,6 +21,10 @@ const (
 type Filter struct {
 	Include []network.NetWithPortRange
 	Exclude []network.NetWithPortRange
+	// RestrictedEndpoints of the execution context are protected from the attack by Apply, see ProtectRestrictedEndpoints.
+	RestrictedEndpoints []action_kit_api.RestrictedEndpoint
+	// RestrictedEndpointsMode defaults to RestrictedEndpointsExclude.
+	RestrictedEndpointsMode RestrictedEndpointsMode
 }
 
 // Opts is the common contract every netfault attack implements. Subsystem-
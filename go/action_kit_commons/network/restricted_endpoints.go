@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package network
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// RestrictedEndpointsToExcludes converts the restricted endpoints of the execution context into excludes.
+// The comment of each exclude carries the name of the endpoint. Endpoints with an invalid CIDR are skipped and reported in the returned error,
+// the excludes of the valid endpoints are returned nevertheless. Endpoints configured only by URL, without a resolved CIDR, are skipped with a warning.
+func RestrictedEndpointsToExcludes(endpoints []action_kit_api.RestrictedEndpoint) ([]NetWithPortRange, error) {
+	var excludes []NetWithPortRange
+	var errs []error
+	for _, endpoint := range endpoints {
+		if endpoint.Cidr == "" {
+			log.Warn().Str("endpoint", cmp.Or(endpoint.Name, endpoint.Url)).Msg("Skipping restricted endpoint without cidr")
+			continue
+		}
+		cidr, err := ParseCIDR(endpoint.Cidr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restricted endpoint %q has an invalid cidr: %w", cmp.Or(endpoint.Name, endpoint.Url), err))
+			continue
+		}
+
+		comment := endpoint.Name
+		if comment == "" {
+			comment = endpoint.Url
+		}
+		excludes = append(excludes, NetWithPortRange{
+			Net:       *cidr,
+			PortRange: restrictedEndpointPortRange(endpoint),
+			Comment:   comment,
+		})
+	}
+	return excludes, errors.Join(errs...)
+}
+
+func restrictedEndpointPortRange(endpoint action_kit_api.RestrictedEndpoint) PortRange {
+	if endpoint.PortMin <= 0 && endpoint.PortMax <= 0 {
+		return PortRangeAny
+	}
+	from := max(endpoint.PortMin, int(PortRangeAny.From))
+	to := endpoint.PortMax
+	if to <= 0 {
+		to = from
+	}
+	to = min(to, int(PortRangeAny.To))
+	if from > to {
+		return PortRangeAny
+	}
+	return PortRange{From: uint16(from), To: uint16(to)}
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package network
+
+import (
+	"testing"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestrictedEndpointsToExcludes(t *testing.T) {
+	excludes, err := RestrictedEndpointsToExcludes([]action_kit_api.RestrictedEndpoint{
+		{Name: "platform", Cidr: "10.0.0.1/32", PortMin: 443, PortMax: 443},
+		{Url: "https://agent", Cidr: "10.0.1.0/24"},
+		{Name: "ranged", Cidr: "fd00::1", PortMin: 8080, PortMax: 8090},
+		{Name: "invalid", Cidr: "not-a-cidr"},
+		{Name: "url only", Url: "https://platform"},
+	})
+
+	assert.Equal(t, []NetWithPortRange{
+		withComment(mustParseNetWithPortRange("10.0.0.1/32", "443"), "platform"),
+		withComment(mustParseNetWithPortRange("10.0.1.0/24", "*"), "https://agent"),
+		withComment(mustParseNetWithPortRange("fd00::1", "8080-8090"), "ranged"),
+	}, excludes)
+	assert.ErrorContains(t, err, `restricted endpoint "invalid" has an invalid cidr`)
+}
+
+func withComment(nwp NetWithPortRange, comment string) NetWithPortRange {
+	nwp.Comment = comment
+	return nwp
+}
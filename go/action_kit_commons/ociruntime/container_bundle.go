@@ -12,7 +12,6 @@ import (
 	"path/filepath"
 	"strconv"
 
-	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
 )
 
@@ -61,7 +60,7 @@ func (b *containerBundle) mountRootfsOverlay(ctx context.Context, image string)
 		return fmt.Errorf("failed to create directory '%s': %w", rootfs, err)
 	}
 
-	log.Trace().
+	utils.Logger(ctx).Trace().
 		Str("lowerdir", image).
 		Str("upper", upper).
 		Str("work", work).
@@ -95,7 +94,7 @@ func (b *containerBundle) CopyFileFromProcess(ctx context.Context, pid int, from
 
 func (b *containerBundle) MountFromProcess(ctx context.Context, fromPid int, fromPath, toPath string) error {
 	mountpoint := filepath.Join(b.path, "rootfs", toPath)
-	log.Trace().
+	utils.Logger(ctx).Trace().
 		Int("fromPid", fromPid).
 		Str("fromPath", fromPath).
 		Str("mount-point", mountpoint).
@@ -115,7 +114,7 @@ func (b *containerBundle) MountFromProcess(ctx context.Context, fromPid int, fro
 }
 
 func unmount(ctx context.Context, path string) error {
-	log.Trace().Str("path", path).Msg("unmounting")
+	utils.Logger(ctx).Trace().Str("path", path).Msg("unmounting")
 	out, err := utils.RootCommandContext(ctx, "umount", "-v", path).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("%w: %s", err, out)
@@ -171,7 +171,7 @@ func (r *defaultRuntime) State(ctx context.Context, id string) (*ContainerState,
 		return nil, r.toError(err, stderr)
 	}
 
-	log.Trace().Str("output", string(output)).Str("stderr", string(stderr)).Msg("get container state")
+	utils.Logger(ctx).Trace().Str("output", string(output)).Str("stderr", string(stderr)).Msg("get container state")
 
 	var state ContainerState
 	if err := unmarshalGuarded(output, &state); err != nil {
@@ -196,17 +196,17 @@ func (r *defaultRuntime) Create(ctx context.Context, image string, id string) (C
 		if !success {
 			err := bundle.Remove()
 			if err != nil {
-				log.Warn().Err(err).Msg("failed to run bundle finalizers")
+				utils.Logger(ctx).Warn().Err(err).Msg("failed to run bundle finalizers")
 			}
 		}
 	}()
 
-	log.Trace().Str("bundle", bundle.path).Msg("creating container bundle")
+	utils.Logger(ctx).Trace().Str("bundle", bundle.path).Msg("creating container bundle")
 	if err := os.MkdirAll(bundle.path, 0775); err != nil {
 		return nil, fmt.Errorf("failed to create directory '%s': %w", bundle.path, err)
 	}
 	bundle.addFinalizer(func() error {
-		log.Trace().Str("bundle", bundle.path).Msg("removing container bundle")
+		utils.Logger(ctx).Trace().Str("bundle", bundle.path).Msg("removing container bundle")
 		return os.RemoveAll(bundle.path)
 	})
 
@@ -217,7 +217,7 @@ func (r *defaultRuntime) Create(ctx context.Context, image string, id string) (C
 		if abs, err := filepath.Abs(image); err == nil {
 			imagePath = abs
 		} else {
-			log.Debug().Err(err).Str("image", image).Msg("failed to get absolute path for image")
+			utils.Logger(ctx).Debug().Err(err).Str("image", image).Msg("failed to get absolute path for image")
 			imagePath = image
 		}
 	} else {
@@ -236,13 +236,13 @@ func (r *defaultRuntime) Create(ctx context.Context, image string, id string) (C
 		return nil, fmt.Errorf("failed to create container spec: %w", err)
 	}
 
-	log.Trace().Str("bundle", bundle.path).Str("id", id).Msg("prepared container bundle")
+	utils.Logger(ctx).Trace().Str("bundle", bundle.path).Str("id", id).Msg("prepared container bundle")
 	success = true
 	return &bundle, nil
 }
 
 func (r *defaultRuntime) Delete(ctx context.Context, id string, force bool) error {
-	log.Trace().Str("id", id).Msg("deleting container")
+	utils.Logger(ctx).Trace().Str("id", id).Msg("deleting container")
 
 	var args = []string{"delete"}
 	if force {
@@ -287,7 +287,7 @@ func (r *defaultRuntime) Run(ctx context.Context, container ContainerBundle, ioO
 	cmd.Stdout = ioOpts.Stdout
 	cmd.Stderr = ioOpts.Stderr
 
-	log.Trace().Str("id", container.ContainerId()).Msg("running container")
+	utils.Logger(ctx).Trace().Str("id", container.ContainerId()).Msg("running container")
 	err = cmd.Run()
 
 	// ProcessState is nil if the process never started (e.g. the runtime binary is
@@ -296,7 +296,7 @@ func (r *defaultRuntime) Run(ctx context.Context, container ContainerBundle, ioO
 	if cmd.ProcessState != nil {
 		exitCode = cmd.ProcessState.ExitCode()
 	}
-	log.Trace().Str("id", container.ContainerId()).Int("exitCode", exitCode).Msg("container exited")
+	utils.Logger(ctx).Trace().Str("id", container.ContainerId()).Int("exitCode", exitCode).Msg("container exited")
 	return err
 }
 
@@ -310,7 +310,7 @@ func (r *defaultRuntime) RunCommand(ctx context.Context, container ContainerBund
 }
 
 func (r *defaultRuntime) Kill(ctx context.Context, id string, signal syscall.Signal) error {
-	log.Trace().Str("id", id).Int("signal", int(signal)).Msg("sending signal to container")
+	utils.Logger(ctx).Trace().Str("id", id).Int("signal", int(signal)).Msg("sending signal to container")
 	if output, err := r.command(ctx, "kill", id, strconv.Itoa(int(signal))).CombinedOutput(); err != nil {
 		return r.toError(err, output)
 	}
@@ -389,7 +389,7 @@ func (r *defaultRuntime) createSpec(_ context.Context, bundle string) error {
 func (r *defaultRuntime) command(ctx context.Context, args ...string) *exec.Cmd {
 	runtimeArgs := append(r.defaultArgs(), args...)
 	nsenterArgs := append([]string{"-t", "1", "-C", "--", r.cfg.Path}, runtimeArgs...)
-	log.Trace().Str("path", r.cfg.Path).Strs("args", runtimeArgs).Msg("exec oci-runtime")
+	utils.Logger(ctx).Trace().Str("path", r.cfg.Path).Strs("args", runtimeArgs).Msg("exec oci-runtime")
 	return utils.RootCommandContext(ctx, nsenterPath, nsenterArgs...)
 }
 
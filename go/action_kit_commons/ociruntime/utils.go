@@ -46,7 +46,7 @@ func RunBundleInBackground(ctx context.Context, runc OciRuntime, bundle Containe
 		return nil, fmt.Errorf("failed to run %s: %w", bundle.ContainerId(), err)
 	}
 
-	logger := log.With().Str("id", bundle.ContainerId()).Logger()
+	logger := utils.Logger(ctx).With().Str("id", bundle.ContainerId()).Logger()
 
 	return utils.RunCommandInBackground(cmd, logger)
 }
@@ -112,7 +112,7 @@ func listNamespaces(ctx context.Context, pid int, types ...specs.LinuxNamespaceT
 		return namespaces[i].Inode < namespaces[j].Inode
 	})
 
-	log.Debug().Msgf("Listed namespaces for pid %d and types %v: %+v", pid, types, namespaces)
+	utils.Logger(ctx).Debug().Msgf("Listed namespaces for pid %d and types %v: %+v", pid, types, namespaces)
 
 	return namespaces, nil
 }
@@ -126,13 +126,13 @@ func executeReadInodes(ctx context.Context, paths ...string) ([]uint64, error) {
 	cmd.Stderr = &serr
 	err := cmd.Run()
 
-	log.Trace().
+	utils.Logger(ctx).Trace().
 		Str("out", sout.String()).
 		Str("err", serr.String()).
 		Msgf("Executed stat command: %v", cmd.Args)
 
 	if err != nil {
-		log.Trace().Err(err).Msgf("failed to read inode(s) of %s", paths)
+		utils.Logger(ctx).Trace().Err(err).Msgf("failed to read inode(s) of %s", paths)
 		var exitError *exec.ExitError
 		if errors.As(err, &exitError) {
 			exitCode := exitError.ExitCode()
@@ -149,7 +149,7 @@ func executeReadInodes(ctx context.Context, paths ...string) ([]uint64, error) {
 		if line != "" {
 			inode, err := strconv.ParseUint(strings.TrimSpace(line), 10, 64)
 			if err != nil {
-				log.Trace().Err(err).Msgf("failed to parse inode %s", line)
+				utils.Logger(ctx).Trace().Err(err).Msgf("failed to parse inode %s", line)
 				continue
 			}
 			inodes = append(inodes, inode)
@@ -207,7 +207,7 @@ func executeListNamespacesFilesystem(ctx context.Context, pid int, nsTypes ...sp
 	links, err := executeReadlinkInProc(ctx, nsPaths...)
 	if err != nil {
 		// Don't return an error as the given pid could already be gone.
-		log.Debug().Err(err).Int("pid", pid).Msg("failed to read links for namespaces of pid")
+		utils.Logger(ctx).Debug().Err(err).Int("pid", pid).Msg("failed to read links for namespaces of pid")
 		return nil, nil
 	}
 
@@ -225,7 +225,7 @@ func executeListNamespacesFilesystem(ctx context.Context, pid int, nsTypes ...sp
 			// No better namespace found, build up path manually.
 			// nsPaths cannot be used, as it may contain missing types and, hence, no result
 			// in the readlink response.
-			log.Warn().
+			utils.Logger(ctx).Warn().
 				Err(err).
 				Str("type", string(nsType)).
 				Int("pid", pid).
@@ -251,7 +251,7 @@ func executeReadlinkUsingExec(ctx context.Context, nsPaths ...string) ([]string,
 	if err != nil {
 		// If one of the given paths does not exist, readlink exits with code 1
 		// but still returns the available paths. Only log the error and proceed.
-		log.Trace().Err(err).
+		utils.Logger(ctx).Trace().Err(err).
 			Str("out", sout.String()).
 			Str("err", serr.String()).
 			Msgf("Executed readlink")
@@ -339,7 +339,7 @@ func refreshNamespace(ctx context.Context, ns *LinuxNamespace) {
 	}
 
 	if HasNamedNetworkNamespace(*ns) {
-		log.Trace().
+		utils.Logger(ctx).Trace().
 			Str("type", string(ns.Type)).
 			Str("path", ns.Path).
 			Uint64("inode", ns.Inode).
@@ -348,14 +348,14 @@ func refreshNamespace(ctx context.Context, ns *LinuxNamespace) {
 	}
 
 	if _, err := os.Lstat(ns.Path); err == nil {
-		log.Trace().
+		utils.Logger(ctx).Trace().
 			Str("type", string(ns.Type)).
 			Str("path", ns.Path).
 			Uint64("inode", ns.Inode).
 			Msg("namespace path still existing, no need to refresh")
 		return
 	} else {
-		log.Trace().
+		utils.Logger(ctx).Trace().
 			Str("type", string(ns.Type)).
 			Str("path", ns.Path).
 			Uint64("inode", ns.Inode).
@@ -370,13 +370,13 @@ func refreshNamespace(ctx context.Context, ns *LinuxNamespace) {
 
 	if err == nil {
 		ns.Path = nsPath
-		log.Trace().
+		utils.Logger(ctx).Trace().
 			Str("type", string(ns.Type)).
 			Str("path", ns.Path).
 			Uint64("inode", ns.Inode).
 			Msg("refreshed namespace")
 	} else {
-		log.Warn().
+		utils.Logger(ctx).Warn().
 			Err(err).
 			Str("type", string(ns.Type)).
 			Str("path", ns.Path).
@@ -392,7 +392,7 @@ func lookupNamedNetworkNamespace(ctx context.Context, targetInode uint64) (strin
 	cmd.Stderr = &serr
 	err := cmd.Run()
 
-	log.Trace().
+	utils.Logger(ctx).Trace().
 		Str("out", sout.String()).
 		Str("err", serr.String()).
 		Msgf("Executed ip command: %v", cmd.Args)
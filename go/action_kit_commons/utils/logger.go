@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package utils
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger returns the logger attached to the context, e.g. the logger of the execution passed by the action_kit_sdk to the lifecycle calls.
+// Falls back to the global logger if the context has no (or a disabled) logger.
+func Logger(ctx context.Context) *zerolog.Logger {
+	if ctx != nil {
+		if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+			return logger
+		}
+	}
+	return &log.Logger
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).With().Str("executionId", "42").Logger()
+
+	Logger(logger.WithContext(context.Background())).Info().Msg("hello")
+
+	assert.JSONEq(t, `{"level":"info","executionId":"42","message":"hello"}`, buf.String())
+}
+
+func TestLogger_falls_back_to_global_logger(t *testing.T) {
+	assert.Same(t, &log.Logger, Logger(context.Background()))
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	extension_kit "github.com/steadybit/extension-kit"
+)
+
+// ActionError is an error with an ActionKitErrorStatus. Return it from the lifecycle methods of an action to tell the platform
+// whether the system under test misbehaved (NewFailedError) or the action could not be performed (NewErroredError).
+// It is recognized when wrapped, too.
+type ActionError struct {
+	Status action_kit_api.ActionKitErrorStatus
+	Title  string
+	Detail *string
+	cause  error
+}
+
+// NewFailedError creates an error with status failed, e.g. for a check detecting a misbehaving system. The detail is taken from cause, if given.
+func NewFailedError(title string, cause error) *ActionError {
+	return newActionError(action_kit_api.Failed, title, cause)
+}
+
+// NewErroredError creates an error with status errored for technical errors of the action. The detail is taken from cause, if given.
+func NewErroredError(title string, cause error) *ActionError {
+	return newActionError(action_kit_api.Errored, title, cause)
+}
+
+func newActionError(status action_kit_api.ActionKitErrorStatus, title string, cause error) *ActionError {
+	err := &ActionError{Status: status, Title: title, cause: cause}
+	if cause != nil {
+		err.Detail = new(cause.Error())
+	}
+	return err
+}
+
+func (e *ActionError) Error() string {
+	if e.Detail != nil {
+		return e.Title + ": " + *e.Detail
+	}
+	return e.Title
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.cause
+}
+
+// fromActionKitError converts an error reported by an interceptor into an ActionError, errors without status are reported as errored.
+func fromActionKitError(err *action_kit_api.ActionKitError) error {
+	if err == nil {
+		return nil
+	}
+	status := action_kit_api.Errored
+	if err.Status != nil {
+		status = *err.Status
+	}
+	return &ActionError{Status: status, Title: err.Title, Detail: err.Detail}
+}
+
+// toActionKitError converts errors returned by actions. ActionError keeps its status, ExtensionError (value or pointer) its fields,
+// any other error is reported with failureTitle and the error as detail.
+func toActionKitError(err error, failureTitle string) *action_kit_api.ActionKitError {
+	if err == nil {
+		return nil
+	}
+	var actionError *ActionError
+	if errors.As(err, &actionError) {
+		return &action_kit_api.ActionKitError{
+			Title:  actionError.Title,
+			Detail: actionError.Detail,
+			Status: new(actionError.Status),
+		}
+	}
+
+	var extensionError extension_kit.ExtensionError
+	var extensionErrorPointer *extension_kit.ExtensionError
+	if errors.As(err, &extensionErrorPointer) && extensionErrorPointer != nil {
+		extensionError = *extensionErrorPointer
+	} else if !errors.As(err, &extensionError) {
+		extensionError = extension_kit.ToError(failureTitle, err)
+	}
+	return &action_kit_api.ActionKitError{
+		Title:    extensionError.Title,
+		Detail:   extensionError.Detail,
+		Type:     extensionError.Type,
+		Instance: extensionError.Instance,
+	}
+}
+
+// writeActionKitError writes the error with status code 500 like exthttp.WriteError, but keeps the status of the error.
+func writeActionKitError(w http.ResponseWriter, err *action_kit_api.ActionKitError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+
+	logEvent := log.Error()
+	if err.Detail != nil {
+		logEvent.Str("details", *err.Detail)
+	}
+	logEvent.Msg(err.Title)
+
+	if encodeErr := json.NewEncoder(w).Encode(err); encodeErr != nil {
+		log.Err(encodeErr).Msgf("Failed to write ActionKitError as response body")
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToActionKitError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected *action_kit_api.ActionKitError
+	}{
+		{name: "nil", err: nil, expected: nil},
+		{
+			name:     "failed",
+			err:      NewFailedError("Rollout not ready.", errors.New("0 of 3 replicas ready")),
+			expected: &action_kit_api.ActionKitError{Title: "Rollout not ready.", Detail: new("0 of 3 replicas ready"), Status: new(action_kit_api.Failed)},
+		},
+		{
+			name:     "wrapped errored",
+			err:      fmt.Errorf("stress: %w", NewErroredError("stress-ng not found.", nil)),
+			expected: &action_kit_api.ActionKitError{Title: "stress-ng not found.", Status: new(action_kit_api.Errored)},
+		},
+		{
+			name:     "extension error value",
+			err:      extension_kit.ToError("Invalid config.", errors.New("missing url")),
+			expected: &action_kit_api.ActionKitError{Title: "Invalid config.", Detail: new("missing url")},
+		},
+		{
+			name:     "extension error pointer",
+			err:      new(extension_kit.ToError("Invalid config.", nil)),
+			expected: &action_kit_api.ActionKitError{Title: "Invalid config."},
+		},
+		{
+			name:     "other error",
+			err:      errors.New("boom"),
+			expected: &action_kit_api.ActionKitError{Title: "Failed to start action.", Detail: new("boom")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toActionKitError(tt.err, "Failed to start action."))
+		})
+	}
+}
+
+func TestActionError_unwraps_cause(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := NewErroredError("Failed to reach the target.", cause)
+	assert.ErrorIs(t, err, cause)
+	assert.EqualError(t, err, "Failed to reach the target.: connection refused")
+}
+
+func TestHandleStatus_reports_failed_error(t *testing.T) {
+	useInmemoryStatePersister(t)
+	action := NewExampleAction(make(chan Call, 10))
+	action.statusError = NewFailedError("Check failed.", nil)
+	adapter := newActionHttpAdapter[ExampleState](action)
+
+	body, err := json.Marshal(action_kit_api.ActionStatusRequestBody{ExecutionId: uuid.New(), State: action_kit_api.ActionState{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handleStatus(w, httptest.NewRequest("POST", adapter.description.Status.Path, bytes.NewReader(body)), body)
+
+	var result action_kit_api.StatusResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Check failed.", result.Error.Title)
+	assert.Equal(t, action_kit_api.Failed, *result.Error.Status)
+}
+
+func TestHandleStop_reports_errors_consistently(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedCode   int
+		expectedTitle  string
+		expectedStatus *action_kit_api.ActionKitErrorStatus
+	}{
+		{name: "failed", err: NewFailedError("Leftover processes.", nil), expectedCode: 200, expectedTitle: "Leftover processes.", expectedStatus: new(action_kit_api.Failed)},
+		{name: "extension error pointer", err: new(extension_kit.ToError("Failed to revert.", nil)), expectedCode: 500, expectedTitle: "Failed to revert."},
+		{name: "other error", err: errors.New("boom"), expectedCode: 500, expectedTitle: "Failed to stop action."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useInmemoryStatePersister(t)
+			action := NewExampleAction(make(chan Call, 10))
+			action.stopError = tt.err
+			adapter := newActionHttpAdapter[ExampleState](action, WithStopPolicy(StopPolicy{MaxAttempts: 1}))
+
+			body, err := json.Marshal(action_kit_api.StopActionRequestBody{ExecutionId: uuid.New(), State: action_kit_api.ActionState{}})
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			adapter.handleStop(w, httptest.NewRequest("POST", adapter.description.Stop.Path, bytes.NewReader(body)), body)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			var actionKitError *action_kit_api.ActionKitError
+			if w.Code == 200 {
+				var result action_kit_api.StopResult
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+				actionKitError = result.Error
+			} else {
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actionKitError))
+			}
+			require.NotNil(t, actionKitError)
+			assert.Equal(t, tt.expectedTitle, actionKitError.Title)
+			assert.Equal(t, tt.expectedStatus, actionKitError.Status)
+		})
+	}
+}
+
+func TestHandleQueryMetric_keeps_error_status(t *testing.T) {
+	action := NewExampleAction(make(chan Call, 10))
+	action.queryError = NewErroredError("Metrics backend unavailable.", nil)
+	adapter := newActionHttpAdapter[ExampleState](action)
+
+	body, err := json.Marshal(action_kit_api.QueryMetricsRequestBody{ExecutionId: uuid.New()})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handleQueryMetric(w, httptest.NewRequest("POST", adapter.description.Metrics.Query.Endpoint.Path, bytes.NewReader(body)), body)
+
+	assert.Equal(t, 500, w.Code)
+	var actionKitError action_kit_api.ActionKitError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &actionKitError))
+	assert.Equal(t, "Metrics backend unavailable.", actionKitError.Title)
+	assert.Equal(t, action_kit_api.Errored, *actionKitError.Status)
+}
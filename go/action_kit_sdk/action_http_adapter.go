@@ -4,23 +4,20 @@
 package action_kit_sdk
 
 import (
-	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
 	extension_kit "github.com/steadybit/extension-kit"
-	"github.com/steadybit/extension-kit/extconversion"
 	"github.com/steadybit/extension-kit/exthttp"
 	"github.com/steadybit/extension-kit/extruntime"
 	"github.com/steadybit/extension-kit/extutil"
@@ -35,14 +32,20 @@ type actionHttpAdapter[T any] struct {
 	description action_kit_api.ActionDescription
 	action      Action[T]
 	rootPath    string
+	options     actionOptions
+	// admissionMu serializes the admission checks, so that concurrent prepares can't both pass the concurrency limits.
+	admissionMu sync.Mutex
+	// reservations holds the targets of the admitted executions whose prepare is still running.
+	reservations map[uuid.UUID]*action_kit_api.Target
 }
 
-func newActionHttpAdapter[T any](action Action[T]) *actionHttpAdapter[T] {
+func newActionHttpAdapter[T any](action Action[T], opts ...ActionOption) *actionHttpAdapter[T] {
 	description := getDescriptionWithDefaults(action)
 	adapter := &actionHttpAdapter[T]{
 		description: description,
 		action:      action,
 		rootPath:    fmt.Sprintf("/%s", description.Id),
+		options:     newActionOptions(opts...),
 	}
 	if adapter.hasQueryMetric() {
 		if adapter.description.Metrics == nil {
@@ -71,6 +74,12 @@ func newActionHttpAdapter[T any](action Action[T]) *actionHttpAdapter[T] {
 	if description.TimeControl == action_kit_api.TimeControlInstantaneous && adapter.hasStop() {
 		log.Fatal().Msgf("Actions using TimeControl 'Instantaneous' should not implement ActionWithStop.")
 	}
+	if adapter.options.hasAdmissionControl() && !adapter.hasStop() {
+		log.Fatal().Msgf("Actions limiting concurrent executions need to implement ActionWithStop.")
+	}
+	if err := checkStateEncryption(action.NewEmptyState()); err != nil {
+		log.Fatal().Err(err).Msgf("Action %s can't be registered.", description.Id)
+	}
 	return adapter
 }
 
@@ -79,12 +88,75 @@ func (a *actionHttpAdapter[T]) handleGetDescription(w http.ResponseWriter, _ *ht
 }
 
 func (a *actionHttpAdapter[T]) handlePrepare(w http.ResponseWriter, r *http.Request, body []byte) {
-	prepareActionRequestBody := parseRequestAndHandleFiles(w, r, body)
+	prepareActionRequestBody, uploadedFiles := a.parseRequestAndHandleFiles(w, r, body)
 	if prepareActionRequestBody == nil {
 		return
 	}
+	prepared := false
+	if len(uploadedFiles) > 0 {
+		defer func() {
+			if !prepared {
+				removeUploadFolder(prepareActionRequestBody.ExecutionId)
+			}
+		}()
+	}
 	state := a.action.NewEmptyState()
-	result, err := a.action.Prepare(r.Context(), &state, *prepareActionRequestBody)
+
+	if !a.options.skipParameterValidation {
+		if violations := validateConfig(a.description.Parameters, prepareActionRequestBody.Config); len(violations) > 0 {
+			var convertedState action_kit_api.ActionState
+			if err := a.options.encodeState(state, &convertedState); err != nil {
+				exthttp.WriteError(w, extension_kit.ToError("Failed to encode action state.", err))
+				return
+			}
+			validationError := toParameterValidationError(violations)
+			a.auditPrepare(r.Context(), prepareActionRequestBody, AuditPrepareRejected, rejectedByValidation, validationError)
+			exthttp.WriteBody(w, action_kit_api.PrepareResult{
+				State: convertedState,
+				Error: validationError,
+			})
+			return
+		}
+	}
+
+	if a.options.hasAdmissionControl() {
+		release, rejection := a.reserveAdmission(r.Context(), prepareActionRequestBody.ExecutionId, prepareActionRequestBody.Target)
+		if rejection != nil {
+			a.auditPrepare(r.Context(), prepareActionRequestBody, AuditPrepareRejected, rejectedByAdmission, rejection)
+			var convertedState action_kit_api.ActionState
+			if err := a.options.encodeState(state, &convertedState); err != nil {
+				exthttp.WriteError(w, extension_kit.ToError("Failed to encode action state.", err))
+				return
+			}
+			exthttp.WriteBody(w, action_kit_api.PrepareResult{
+				State: convertedState,
+				Error: rejection,
+			})
+			return
+		}
+		// the reservation is kept until the state is persisted, so that the execution is visible to the next admission check
+		defer release()
+	}
+
+	var result *action_kit_api.PrepareResult
+	var err error
+	fields := logFields(prepareActionRequestBody.Target, prepareActionRequestBody.ExecutionContext)
+	ctx := contextWithExecutionLogger(contextWithUploadedFiles(r.Context(), uploadedFiles), a.description.Id, prepareActionRequestBody.ExecutionId, fields)
+	ctx, done := inflight.track(ctx, prepareActionRequestBody.ExecutionId, PhasePrepare)
+	defer done()
+	call := &LifecycleCall{Phase: PhasePrepare, ExecutionId: prepareActionRequestBody.ExecutionId, Request: prepareActionRequestBody}
+	invoked := false
+	prepareError := a.intercept(ctx, call, &state, "Failed to prepare.", func(ctx context.Context) error {
+		invoked = true
+		result, err = callRecovering(func() (*action_kit_api.PrepareResult, error) {
+			return a.action.Prepare(ctx, &state, *prepareActionRequestBody)
+		})
+		return err
+	})
+	var panicked *actionPanic
+	if errors.As(err, &panicked) {
+		prepareError = a.handlePanic(panicked, prepareActionRequestBody.ExecutionId, PhasePrepare)
+	}
 	if result == nil {
 		result = &action_kit_api.PrepareResult{}
 	}
@@ -105,94 +177,61 @@ func (a *actionHttpAdapter[T]) handlePrepare(w http.ResponseWriter, r *http.Requ
 	}
 
 	var convertedState action_kit_api.ActionState
-	conversionErr := extconversion.Convert(state, &convertedState)
+	conversionErr := a.options.encodeState(state, &convertedState)
 	if conversionErr != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Failed to encode action state.", conversionErr))
 		return
 	}
+	withLogFields(convertedState, fields)
 	result.State = convertedState
-
-	if err != nil {
-		var extensionError *extension_kit.ExtensionError
-		isExtensionError := errors.As(err, &extensionError)
-		if !isExtensionError {
-			extensionError = new(extension_kit.ToError("Failed to prepare.", err))
-		}
-		result.Error = &action_kit_api.ActionKitError{
-			Title:    extensionError.Title,
-			Detail:   extensionError.Detail,
-			Type:     extensionError.Type,
-			Instance: extensionError.Instance,
-		}
+	if prepareError != nil {
+		result.Error = prepareError
 	}
 
-	if a.description.Stop != nil {
-		err = statePersister.PersistState(r.Context(), &state_persister.PersistedState{ExecutionId: prepareActionRequestBody.ExecutionId, ActionId: a.description.Id, State: convertedState})
+	// failed executions are neither started nor stopped, their state must not take up an admission slot or be recovered
+	if a.description.Stop != nil && result.Error == nil {
+		err = a.persistState(r.Context(), &state_persister.PersistedState{ExecutionId: prepareActionRequestBody.ExecutionId, State: convertedState, Target: prepareActionRequestBody.Target, ExecutionContext: prepareActionRequestBody.ExecutionContext})
 		if err != nil {
 			exthttp.WriteError(w, extension_kit.ToError("Failed to persist action state.", err))
 			return
 		}
 	}
-	exthttp.WriteBody(w, result)
-}
-
-func parseRequestAndHandleFiles(w http.ResponseWriter, r *http.Request, body []byte) *action_kit_api.PrepareActionRequestBody {
-	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
-		err := r.ParseMultipartForm(10 << 20)
-		if err != nil {
-			exthttp.WriteError(w, extension_kit.ToError("Failed to parse multipart request body.", err))
-			return nil
-		}
-		prepareActionRequest := parsePrepareActionRequestBody(w, []byte(r.MultipartForm.Value["request"][0]))
-		folder := fmt.Sprintf("/tmp/steadybit/%v", prepareActionRequest.ExecutionId)
-		err = os.MkdirAll(folder, 0755)
-		for parameterName, fileHeaders := range r.MultipartForm.File {
-			if len(fileHeaders) > 1 {
-				exthttp.WriteError(w, extension_kit.ToError(fmt.Sprintf("Too many Fileheaders for parameter %s.", parameterName), err))
-				return nil
-			}
-			filename := folder + "/" + filepath.Base(fileHeaders[0].Filename)
-			log.Debug().Msgf("Save File: Parameter %s, File %s", parameterName, filename)
-			err := saveFile(filename, fileHeaders[0])
-			if err != nil {
-				exthttp.WriteError(w, extension_kit.ToError(fmt.Sprintf("Failed to save file %s.", filename), err))
-				return nil
-			}
-			prepareActionRequest.Config[parameterName] = filename
-		}
-		return prepareActionRequest
+	prepared = result.Error == nil
+	if !invoked && result.Error != nil {
+		a.auditPrepare(r.Context(), prepareActionRequestBody, AuditPrepareRejected, rejectedByInterceptor, result.Error)
 	} else {
-		return parsePrepareActionRequestBody(w, body)
+		a.auditPrepare(r.Context(), prepareActionRequestBody, AuditPrepared, "", result.Error)
 	}
+	exthttp.WriteBody(w, result)
 }
 
-func saveFile(filename string, fileHeader *multipart.FileHeader) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+func (a *actionHttpAdapter[T]) parseRequestAndHandleFiles(w http.ResponseWriter, r *http.Request, body []byte) (*action_kit_api.PrepareActionRequestBody, map[string]UploadedFile) {
+	if !isMultipartRequest(r) {
+		return parsePrepareActionRequestBody(w, body), nil
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			log.Error().Err(err).Msgf("Failed to close file %s", file.Name())
-		}
-	}(file)
 
-	open, err := fileHeader.Open()
-	if err != nil {
-		return err
+	prepareActionRequest, files, err := a.receiveUploads(r)
+	if errors.Is(err, errAnsweredByPreviousCall) {
+		return nil, nil
 	}
-	buffer := bytes.NewBuffer(nil)
-	if _, err := io.Copy(buffer, open); err != nil {
-		return err
-	}
-	if _, err = file.Write(buffer.Bytes()); err != nil {
-		return err
-	}
-	if err = file.Sync(); err != nil {
-		return err
+	if err != nil {
+		var violation *uploadViolation
+		if errors.As(err, &violation) {
+			var convertedState action_kit_api.ActionState
+			if err := a.options.encodeState(a.action.NewEmptyState(), &convertedState); err != nil {
+				exthttp.WriteError(w, extension_kit.ToError("Failed to encode action state.", err))
+				return nil, nil
+			}
+			exthttp.WriteBody(w, action_kit_api.PrepareResult{
+				State: convertedState,
+				Error: violation.toActionKitError(),
+			})
+		} else {
+			exthttp.WriteError(w, extension_kit.ToError("Failed to parse multipart request body.", err))
+		}
+		return nil, nil
 	}
-	return nil
+	return prepareActionRequest, files
 }
 
 func parsePrepareActionRequestBody(w http.ResponseWriter, request []byte) *action_kit_api.PrepareActionRequestBody {
@@ -213,13 +252,67 @@ func (a *actionHttpAdapter[T]) handleStart(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	state := a.action.NewEmptyState()
-	err = extconversion.Convert(parsedBody.State, &state)
+	err = a.options.decodeState(parsedBody.State, &state)
 	if err != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Failed to parse state.", err))
 		return
 	}
 
-	result, err := a.action.Start(r.Context(), &state)
+	if !starts.enter() {
+		exthttp.WriteBody(w, action_kit_api.StartResult{
+			State: &parsedBody.State,
+			Error: &action_kit_api.ActionKitError{
+				Title:  "The extension is stopping all active actions, start rejected.",
+				Status: new(action_kit_api.Errored),
+			},
+		})
+		return
+	}
+	defer starts.leave()
+
+	if a.options.hasAdmissionControl() {
+		var target *action_kit_api.Target
+		if persisted, err := statePersister.GetState(r.Context(), parsedBody.ExecutionId); err == nil {
+			target = persisted.Target
+		}
+		a.admissionMu.Lock()
+		rejection := checkAdmission(r.Context(), a.description.Id, a.options, parsedBody.ExecutionId, target, a.reservations)
+		a.admissionMu.Unlock()
+		if rejection != nil {
+			exthttp.WriteBody(w, action_kit_api.StartResult{
+				State: &parsedBody.State,
+				Error: rejection,
+			})
+			return
+		}
+	}
+
+	fields := logFieldsFromState(parsedBody.State)
+	ctx := contextWithExecutionLogger(r.Context(), a.description.Id, parsedBody.ExecutionId, fields)
+	ctx, done := inflight.track(ctx, parsedBody.ExecutionId, PhaseStart)
+	defer done()
+	if a.description.Status != nil {
+		ctx = contextWithExecutionSink(ctx, getOrCreateExecutionSink(parsedBody.ExecutionId, a.options.executionSinkSize))
+	}
+	var result *action_kit_api.StartResult
+	call := &LifecycleCall{Phase: PhaseStart, ExecutionId: parsedBody.ExecutionId, Request: &parsedBody}
+	startError := a.intercept(ctx, call, &state, "Failed to start action.", func(ctx context.Context) error {
+		result, err = callRecovering(func() (*action_kit_api.StartResult, error) {
+			return a.action.Start(ctx, &state)
+		})
+		return err
+	})
+	var panicked *actionPanic
+	if errors.As(err, &panicked) {
+		done()
+		panicError := a.handlePanic(panicked, parsedBody.ExecutionId, PhaseStart)
+		audit(r.Context(), AuditEvent{Type: AuditStarted, ActionId: a.description.Id, ExecutionId: parsedBody.ExecutionId, Error: panicError})
+		exthttp.WriteBody(w, action_kit_api.StartResult{
+			State: &parsedBody.State,
+			Error: panicError,
+		})
+		return
+	}
 	if result == nil {
 		result = &action_kit_api.StartResult{}
 	}
@@ -229,47 +322,74 @@ func (a *actionHttpAdapter[T]) handleStart(w http.ResponseWriter, r *http.Reques
 	}
 
 	var convertedState action_kit_api.ActionState
-	conversionErr := extconversion.Convert(state, &convertedState)
+	conversionErr := a.options.encodeState(state, &convertedState)
 	if conversionErr != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Failed to encode action state.", conversionErr))
 		return
 	}
+	withLogFields(convertedState, fields)
 	result.State = &convertedState
-
-	if err != nil {
-		var extensionError *extension_kit.ExtensionError
-		isExtensionError := errors.As(err, &extensionError)
-		if !isExtensionError {
-			extensionError = new(extension_kit.ToError("Failed to start action.", err))
-		}
-		result.Error = &action_kit_api.ActionKitError{
-			Title:    extensionError.Title,
-			Detail:   extensionError.Detail,
-			Type:     extensionError.Type,
-			Instance: extensionError.Instance,
-		}
+	if startError != nil {
+		result.Error = startError
 	}
 
 	if a.description.Stop != nil {
-		err = statePersister.PersistState(r.Context(), &state_persister.PersistedState{ExecutionId: parsedBody.ExecutionId, ActionId: a.description.Id, State: convertedState})
+		err = a.persistState(r.Context(), &state_persister.PersistedState{ExecutionId: parsedBody.ExecutionId, State: convertedState, StartedAt: new(time.Now())})
 		if err != nil {
 			exthttp.WriteError(w, extension_kit.ToError("Failed to persist action state.", err))
 			return
 		}
 
-		if (a.description.Status != nil) && (a.description.Status.CallInterval != nil) {
-			interval, err := time.ParseDuration(*a.description.Status.CallInterval)
-			if interval < minHeartbeatInterval {
-				interval = minHeartbeatInterval
-			}
-			if err == nil {
-				monitorHeartbeat(parsedBody.ExecutionId, interval, interval*4)
-			}
-		}
+		a.startHeartbeatMonitor(parsedBody.ExecutionId)
+	} else if a.description.Status != nil {
+		a.startHeartbeatMonitor(parsedBody.ExecutionId)
 	}
+	audit(r.Context(), AuditEvent{Type: AuditStarted, ActionId: a.description.Id, ExecutionId: parsedBody.ExecutionId, Error: result.Error})
 	exthttp.WriteBody(w, result)
 }
 
+func (a *actionHttpAdapter[T]) startHeartbeatMonitor(executionId uuid.UUID) {
+	policy := a.options.heartbeatPolicy
+	if isHeartbeatDisabled(a.description, policy) {
+		log.Debug().
+			Str("actionId", a.description.Id).
+			Str("executionId", executionId.String()).
+			Msg("heartbeat monitoring is disabled")
+		return
+	}
+	if a.description.Status == nil || a.description.Status.CallInterval == nil {
+		return
+	}
+	callInterval, err := time.ParseDuration(*a.description.Status.CallInterval)
+	if err != nil {
+		return
+	}
+	interval, timeout := policy.heartbeatTimings(callInterval)
+	if a.description.Stop == nil {
+		// there is nothing to stop, but the execution sink must be released if the agent is gone before the action completed
+		monitorHeartbeatWithCallback(executionId, interval, timeout, func() {
+			stopMonitorHeartbeat(executionId)
+			removeExecutionSink(executionId)
+		})
+		return
+	}
+	monitorHeartbeat(executionId, interval, timeout, policy.OnTimeout)
+}
+
+// persistState stores the state of the execution. Target, start time and execution context which are not set are kept from the previously persisted state.
+func (a *actionHttpAdapter[T]) persistState(ctx context.Context, persisted *state_persister.PersistedState) error {
+	persisted.ActionId = a.description.Id
+	persisted.ProcessId = processId
+	if persisted.Target == nil || persisted.StartedAt == nil || persisted.ExecutionContext == nil {
+		if previous, err := statePersister.GetState(ctx, persisted.ExecutionId); err == nil {
+			persisted.Target = cmp.Or(persisted.Target, previous.Target)
+			persisted.StartedAt = cmp.Or(persisted.StartedAt, previous.StartedAt)
+			persisted.ExecutionContext = cmp.Or(persisted.ExecutionContext, previous.ExecutionContext)
+		}
+	}
+	return statePersister.PersistState(ctx, persisted)
+}
+
 func (a *actionHttpAdapter[T]) hasStatus() bool {
 	_, ok := a.action.(ActionWithStatus[T])
 	return ok
@@ -286,69 +406,98 @@ func (a *actionHttpAdapter[T]) handleStatus(w http.ResponseWriter, r *http.Reque
 	recordHeartbeat(parsedBody.ExecutionId)
 
 	if stopEvent := getStopEvent(parsedBody.ExecutionId); stopEvent != nil {
+		statusError := &action_kit_api.ActionKitError{
+			Title:  fmt.Sprintf("Action was stopped by extension: %s", stopEvent.reason),
+			Status: extutil.Ptr(action_kit_api.Errored),
+		}
+		if leftover := getLeftover(r.Context(), parsedBody.ExecutionId); leftover != nil {
+			statusError.Detail = new(leftover.message())
+		}
 		exthttp.WriteBody(w, action_kit_api.StatusResult{
 			Completed: true,
-			Error: &action_kit_api.ActionKitError{
-				Title:  fmt.Sprintf("Action was stopped by extension: %s", stopEvent.reason),
-				Status: extutil.Ptr(action_kit_api.Errored),
-			},
+			Error:     statusError,
 		})
 		return
 	}
 
+	sink := getExecutionSink(parsedBody.ExecutionId)
 	action, ok := a.action.(ActionWithStatus[T])
 	if !ok {
-		exthttp.WriteBody(w, action_kit_api.StatusResult{
+		result := action_kit_api.StatusResult{
 			Completed: false,
-		})
+		}
+		drainExecutionSink(sink, &result.Messages, &result.Metrics)
+		exthttp.WriteBody(w, result)
 		return
 	}
 
 	state := action.NewEmptyState()
-	err = extconversion.Convert(parsedBody.State, &state)
+	err = a.options.decodeState(parsedBody.State, &state)
 	if err != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Failed to parse state.", err))
 		return
 	}
 
-	result, err := action.Status(r.Context(), &state)
+	var result *action_kit_api.StatusResult
+	call := &LifecycleCall{Phase: PhaseStatus, ExecutionId: parsedBody.ExecutionId, Request: &parsedBody}
+	fields := logFieldsFromState(parsedBody.State)
+	ctx := contextWithExecutionLogger(contextWithExecutionSink(r.Context(), sink), a.description.Id, parsedBody.ExecutionId, fields)
+	ctx, done := inflight.track(ctx, parsedBody.ExecutionId, PhaseStatus)
+	defer done()
+	statusError := a.intercept(ctx, call, &state, "Failed to read status.", func(ctx context.Context) error {
+		result, err = callRecovering(func() (*action_kit_api.StatusResult, error) {
+			return action.Status(ctx, &state)
+		})
+		return err
+	})
+	var panicked *actionPanic
+	if errors.As(err, &panicked) {
+		stopMonitorHeartbeat(parsedBody.ExecutionId)
+		done()
+		panicError := a.handlePanic(panicked, parsedBody.ExecutionId, PhaseStatus)
+		audit(r.Context(), AuditEvent{Type: AuditStatusCompleted, ActionId: a.description.Id, ExecutionId: parsedBody.ExecutionId, Error: panicError})
+		exthttp.WriteBody(w, action_kit_api.StatusResult{
+			Completed: true,
+			State:     &parsedBody.State,
+			Error:     panicError,
+		})
+		return
+	}
 	if result == nil {
 		result = &action_kit_api.StatusResult{}
 	}
+	drainExecutionSink(sink, &result.Messages, &result.Metrics)
+	if result.Completed && a.description.Stop == nil {
+		stopMonitorHeartbeat(parsedBody.ExecutionId)
+		removeExecutionSink(parsedBody.ExecutionId)
+	}
 
 	if result.State != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Please modify the state using the given state pointer.", err))
 	}
 
 	var convertedState action_kit_api.ActionState
-	conversionErr := extconversion.Convert(state, &convertedState)
+	conversionErr := a.options.encodeState(state, &convertedState)
 	if conversionErr != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Failed to encode action state.", conversionErr))
 		return
 	}
+	withLogFields(convertedState, fields)
 	result.State = &convertedState
-
-	if err != nil {
-		var extensionError *extension_kit.ExtensionError
-		isExtensionError := errors.As(err, &extensionError)
-		if !isExtensionError {
-			extensionError = new(extension_kit.ToError("Failed to read status.", err))
-		}
-		result.Error = &action_kit_api.ActionKitError{
-			Title:    extensionError.Title,
-			Detail:   extensionError.Detail,
-			Type:     extensionError.Type,
-			Instance: extensionError.Instance,
-		}
+	if statusError != nil {
+		result.Error = statusError
 	}
 
 	if a.description.Stop != nil {
-		err = statePersister.PersistState(r.Context(), &state_persister.PersistedState{ExecutionId: parsedBody.ExecutionId, ActionId: a.description.Id, State: convertedState})
+		err = a.persistState(r.Context(), &state_persister.PersistedState{ExecutionId: parsedBody.ExecutionId, State: convertedState})
 		if err != nil {
 			exthttp.WriteError(w, extension_kit.ToError("Failed to persist action state.", err))
 			return
 		}
 	}
+	if result.Completed {
+		audit(r.Context(), AuditEvent{Type: AuditStatusCompleted, ActionId: a.description.Id, ExecutionId: parsedBody.ExecutionId, Error: result.Error})
+	}
 	exthttp.WriteBody(w, result)
 }
 
@@ -368,6 +517,8 @@ func (a *actionHttpAdapter[T]) handleStop(w http.ResponseWriter, r *http.Request
 	}
 
 	stopMonitorHeartbeat(parsedBody.ExecutionId)
+	sink := getExecutionSink(parsedBody.ExecutionId)
+	defer removeExecutionSink(parsedBody.ExecutionId)
 
 	if stopEvent := getStopEvent(parsedBody.ExecutionId); stopEvent != nil {
 		exthttp.WriteBody(w, action_kit_api.StopResult{
@@ -379,36 +530,41 @@ func (a *actionHttpAdapter[T]) handleStop(w http.ResponseWriter, r *http.Request
 	}
 
 	state := action.NewEmptyState()
-	err = extconversion.Convert(parsedBody.State, &state)
+	err = a.options.decodeState(parsedBody.State, &state)
 	if err != nil {
 		exthttp.WriteError(w, extension_kit.ToError("Failed to parse state.", err))
 		return
 	}
 
-	result, err := action.Stop(r.Context(), &state)
+	var result *action_kit_api.StopResult
+	call := &LifecycleCall{Phase: PhaseStop, ExecutionId: parsedBody.ExecutionId, Request: &parsedBody}
+	ctx := contextWithExecutionLogger(contextWithExecutionSink(r.Context(), sink), a.description.Id, parsedBody.ExecutionId, logFieldsFromState(parsedBody.State))
+	// tracked until the state is deleted, so that stops by the extension wait for it
+	ctx, done := inflight.track(ctx, parsedBody.ExecutionId, PhaseStop)
+	defer done()
+	stopError := a.intercept(ctx, call, &state, "Failed to stop action.", func(ctx context.Context) error {
+		result, err = callStop(ctx, a.options.getStopPolicy(), parsedBody.ExecutionId, a.description.Id, "stop requested by agent", func(ctx context.Context) (*action_kit_api.StopResult, error) {
+			return action.Stop(ctx, &state)
+		})
+		return err
+	})
 	if result == nil {
 		result = &action_kit_api.StopResult{}
 	}
-	if err != nil {
-		extensionError, isExtensionError := err.(extension_kit.ExtensionError)
-		if isExtensionError {
-			exthttp.WriteError(w, extensionError)
+	drainExecutionSink(sink, &result.Messages, &result.Metrics)
+	audit(r.Context(), AuditEvent{Type: AuditStopped, ActionId: a.description.Id, ExecutionId: parsedBody.ExecutionId, Error: stopError})
+	if stopError != nil {
+		if stopError.Status != nil {
+			// errors with status are reported in the result, like for the other lifecycle calls
+			result.Error = stopError
+			exthttp.WriteBody(w, result)
 		} else {
-			exthttp.WriteError(w, extension_kit.ToError("Failed to stop action.", err))
+			writeActionKitError(w, stopError)
 		}
 		return
 	}
 
-	folder := fmt.Sprintf("/tmp/steadybit/%v", parsedBody.ExecutionId)
-	_, err = os.Stat(folder)
-	if !os.IsNotExist(err) {
-		err = os.RemoveAll(folder)
-		if err != nil {
-			log.Error().Msgf("Could not remove directory '%s'", folder)
-		} else {
-			log.Debug().Msgf("Directory '%s' removed successfully", folder)
-		}
-	}
+	removeUploadFolder(parsedBody.ExecutionId)
 
 	err = statePersister.DeleteState(r.Context(), parsedBody.ExecutionId)
 	if err != nil {
@@ -442,12 +598,7 @@ func (a *actionHttpAdapter[T]) handleQueryMetric(w http.ResponseWriter, r *http.
 		result = &action_kit_api.QueryMetricsResult{}
 	}
 	if err != nil {
-		extensionError, isExtensionError := err.(extension_kit.ExtensionError)
-		if isExtensionError {
-			exthttp.WriteError(w, extensionError)
-		} else {
-			exthttp.WriteError(w, extension_kit.ToError("Failed to query metrics.", err))
-		}
+		writeActionKitError(w, toActionKitError(err, "Failed to query metrics."))
 		return
 	}
 	exthttp.WriteBody(w, result)
@@ -456,14 +607,14 @@ func (a *actionHttpAdapter[T]) handleQueryMetric(w http.ResponseWriter, r *http.
 func (a *actionHttpAdapter[T]) registerHandlers() {
 
 	exthttp.RegisterHttpHandler(a.rootPath, a.handleGetDescription)
-	exthttp.RegisterHttpHandler(a.description.Prepare.Path, a.handlePrepare)
-	exthttp.RegisterHttpHandler(a.description.Start.Path, a.handleStart)
+	exthttp.RegisterHttpHandler(a.description.Prepare.Path, a.idempotent(PhasePrepare, a.handlePrepare))
+	exthttp.RegisterHttpHandler(a.description.Start.Path, a.idempotent(PhaseStart, a.handleStart))
 	if a.hasStatus() || a.hasStop() {
 		// If the action has a stop,  we augment a status endpoint. It is used to report stops by extension.
 		exthttp.RegisterHttpHandler(a.description.Status.Path, a.handleStatus)
 	}
 	if a.hasStop() {
-		exthttp.RegisterHttpHandler(a.description.Stop.Path, a.handleStop)
+		exthttp.RegisterHttpHandler(a.description.Stop.Path, a.idempotent(PhaseStop, a.handleStop))
 	}
 	if a.hasQueryMetric() {
 		exthttp.RegisterHttpHandler(a.description.Metrics.Query.Endpoint.Path, a.handleQueryMetric)
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+// ActionOption configures how the SDK handles a registered action. Options are passed to RegisterAction.
+type ActionOption func(*actionOptions)
+
+type actionOptions struct {
+	skipParameterValidation   bool
+	maxConcurrentExecutions   int
+	exclusiveTargetAttributes []string
+	stopPolicy                *StopPolicy
+	heartbeatPolicy           HeartbeatPolicy
+	executionSinkSize         int
+	maxUploadSize             int64
+	interceptors              []Interceptor
+	stateVersion              int
+	stateMigrations           map[int]StateMigration
+}
+
+func newActionOptions(opts ...ActionOption) actionOptions {
+	options := actionOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func (o actionOptions) hasAdmissionControl() bool {
+	return o.maxConcurrentExecutions > 0 || len(o.exclusiveTargetAttributes) > 0
+}
+
+// WithoutParameterValidation disables the validation of the prepare configuration against the parameters of the action description.
+// Use this if the action validates its configuration on its own.
+func WithoutParameterValidation() ActionOption {
+	return func(o *actionOptions) {
+		o.skipParameterValidation = true
+	}
+}
+
+
+// WithMaxConcurrentExecutions limits the number of concurrently active executions of the action.
+// Further executions are rejected in prepare and start. Requires the action to implement ActionWithStop.
+func WithMaxConcurrentExecutions(limit int) ActionOption {
+	return func(o *actionOptions) {
+		o.maxConcurrentExecutions = limit
+	}
+}
+
+// WithExclusiveTargetAttribute allows only one active execution of the action per value of the given target attribute (e.g. "container.id").
+// Further executions for the same target are rejected in prepare and start. Requires the action to implement ActionWithStop.
+func WithExclusiveTargetAttribute(key string) ActionOption {
+	return func(o *actionOptions) {
+		o.exclusiveTargetAttributes = append(o.exclusiveTargetAttributes, key)
+	}
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const recoveryStopReason = "recovered after restart"
+
+// processId identifies this process of the extension in the persisted states, so that recovery only stops executions of previous processes.
+var processId = uuid.NewString()
+
+// RecoveredExecution describes an execution found in the persisted states during RecoverActiveActions.
+type RecoveredExecution struct {
+	ExecutionId uuid.UUID
+	ActionId    string
+	Err         error
+}
+
+// RecoveryReport is the outcome of RecoverActiveActions.
+type RecoveryReport struct {
+	// Stopped contains the executions which were stopped successfully.
+	Stopped []RecoveredExecution
+	// Failed contains the executions for which Stop returned an error or the state could not be loaded. Their state is kept.
+	Failed []RecoveredExecution
+	// Unregistered contains the executions whose action is no longer registered. Their state is kept, they need to be cleaned up manually.
+	Unregistered []RecoveredExecution
+}
+
+// RecoverActiveActions stops all executions left behind by a previous process of this extension. Executions persisted by this process are not
+// touched, so it is safe to call while the extension is serving requests.
+// The persisted states are matched against the registered actions and Stop is invoked with the reason "recovered after restart".
+// This only has an effect with a persister which survives restarts (see SetStatePersister).
+// RegisterAction already recovers the executions of the registered action, call this after all actions have been registered
+// to retry failed executions and to get a report of the executions whose action is no longer registered.
+func RecoverActiveActions(ctx context.Context) RecoveryReport {
+	return recoverActiveActions(ctx, func(string) bool { return true })
+}
+
+// recoverActiveActionsOf stops the executions of the action left behind by a previous process of this extension. Called by RegisterAction.
+func recoverActiveActionsOf(ctx context.Context, actionId string) RecoveryReport {
+	return recoverActiveActions(ctx, func(id string) bool { return id == actionId })
+}
+
+func recoverActiveActions(ctx context.Context, matches func(actionId string) bool) RecoveryReport {
+	report := RecoveryReport{}
+
+	executionIds, err := statePersister.GetExecutionIds(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load persisted action states, cannot recover active actions")
+		return report
+	}
+
+	var recovered int
+	for _, executionId := range executionIds {
+		execution := RecoveredExecution{ExecutionId: executionId}
+		if persistedState, err := statePersister.GetState(ctx, executionId); err == nil {
+			if persistedState.ProcessId == processId {
+				continue
+			}
+			execution.ActionId = persistedState.ActionId
+		}
+		if !matches(execution.ActionId) {
+			continue
+		}
+		recovered++
+
+		err := stopAction(ctx, executionId, recoveryStopReason)
+		switch {
+		case errors.Is(err, errActionNotRegistered):
+			report.Unregistered = append(report.Unregistered, execution)
+		case err != nil:
+			execution.Err = err
+			report.Failed = append(report.Failed, execution)
+		default:
+			report.Stopped = append(report.Stopped, execution)
+		}
+	}
+
+	if recovered > 0 {
+		log.Info().
+			Int("stopped", len(report.Stopped)).
+			Int("failed", len(report.Failed)).
+			Int("unregistered", len(report.Unregistered)).
+			Msg("recovered active actions after restart")
+	}
+	for _, execution := range report.Unregistered {
+		log.Warn().
+			Str("actionId", execution.ActionId).
+			Str("executionId", execution.ExecutionId.String()).
+			Msg("action of recovered execution is no longer registered, manual cleanup required")
+	}
+	return report
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverActiveActions(t *testing.T) {
+	previousPersister, previousActions := statePersister, registeredActions
+	t.Cleanup(func() { statePersister, registeredActions = previousPersister, previousActions })
+
+	dir := t.TempDir()
+	persister, err := state_persister.NewFileStatePersister(dir)
+	require.NoError(t, err)
+	known := uuid.New()
+	unknown := uuid.New()
+	require.NoError(t, persister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: known, ActionId: "ExampleActionId", State: action_kit_api.ActionState{"TestStep": "Start"}}))
+	require.NoError(t, persister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: unknown, ActionId: "RemovedActionId", State: action_kit_api.ActionState{}}))
+
+	// simulate a restart of the extension
+	restarted, err := state_persister.NewFileStatePersister(dir)
+	require.NoError(t, err)
+	SetStatePersister(restarted)
+	calls := make(chan Call, 10)
+	registeredActions = map[string]any{"ExampleActionId": NewExampleAction(calls)}
+
+	report := RecoverActiveActions(context.Background())
+
+	assert.Equal(t, []RecoveredExecution{{ExecutionId: known, ActionId: "ExampleActionId"}}, report.Stopped)
+	assert.Equal(t, []RecoveredExecution{{ExecutionId: unknown, ActionId: "RemovedActionId"}}, report.Unregistered)
+	assert.Empty(t, report.Failed)
+
+	call := <-calls
+	assert.Equal(t, "Stop", call.Name)
+	assert.Equal(t, "Start", call.Args[0].(*ExampleState).TestStep)
+
+	executionIds, err := restarted.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{unknown}, executionIds, "state of unregistered actions must be kept")
+
+	stopEvent := getStopEvent(known)
+	require.NotNil(t, stopEvent)
+	assert.Equal(t, "recovered after restart", stopEvent.reason)
+}
+
+type withoutStopAction struct{}
+
+func (a *withoutStopAction) NewEmptyState() ExampleState { return ExampleState{} }
+func (a *withoutStopAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{Id: "WithoutStopActionId"}
+}
+func (a *withoutStopAction) Prepare(context.Context, *ExampleState, action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	return nil, nil
+}
+func (a *withoutStopAction) Start(context.Context, *ExampleState) (*action_kit_api.StartResult, error) {
+	return nil, nil
+}
+
+func TestRecoverActiveActionsOf_only_recovers_the_action(t *testing.T) {
+	useInmemoryStatePersister(t)
+	previousActions := registeredActions
+	t.Cleanup(func() { registeredActions = previousActions })
+	calls := make(chan Call, 10)
+	registeredActions = map[string]any{"ExampleActionId": NewExampleAction(calls), "WithoutStopActionId": &withoutStopAction{}}
+	example, withoutStop, other := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: example, ActionId: "ExampleActionId", State: action_kit_api.ActionState{}}))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: withoutStop, ActionId: "WithoutStopActionId", State: action_kit_api.ActionState{}}))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: other, ActionId: "NotYetRegisteredActionId", State: action_kit_api.ActionState{}}))
+
+	report := recoverActiveActionsOf(context.Background(), "ExampleActionId")
+	assert.Equal(t, []RecoveredExecution{{ExecutionId: example, ActionId: "ExampleActionId"}}, report.Stopped)
+	assert.Empty(t, report.Unregistered, "executions of other actions are left to their registration")
+	assert.Equal(t, "Stop", (<-calls).Name)
+
+	report = recoverActiveActionsOf(context.Background(), "WithoutStopActionId")
+	assert.Equal(t, []RecoveredExecution{{ExecutionId: withoutStop, ActionId: "WithoutStopActionId"}}, report.Stopped)
+
+	executionIds, err := statePersister.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{other}, executionIds, "states of stopped executions are deleted, also without stop")
+}
+
+func TestRecoverActiveActions_keeps_executions_of_this_process(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	action := NewExampleAction(calls)
+	adapter := newActionHttpAdapter[ExampleState](action, WithoutParameterValidation())
+	registeredActions[adapter.description.Id] = action
+	t.Cleanup(func() { delete(registeredActions, adapter.description.Id) })
+	executionId := uuid.New()
+	require.NoError(t, adapter.persistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, State: action_kit_api.ActionState{}}))
+
+	report := recoverActiveActionsOf(context.Background(), adapter.description.Id)
+
+	assert.Empty(t, report.Stopped)
+	assert.Empty(t, calls, "live executions are not stopped")
+	_, err := statePersister.GetState(context.Background(), executionId)
+	assert.NoError(t, err)
+}
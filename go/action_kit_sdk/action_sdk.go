@@ -5,6 +5,7 @@ package action_kit_sdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -18,7 +19,6 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
-	"github.com/steadybit/extension-kit/extconversion"
 	"github.com/steadybit/extension-kit/extheartbeat"
 	"github.com/steadybit/extension-kit/exthttp"
 	"github.com/steadybit/extension-kit/extsignals"
@@ -26,10 +26,14 @@ import (
 
 var (
 	registeredActions = make(map[string]any)
-	statePersister    = state_persister.NewInmemoryStatePersister()
-	stopEvents        = make([]stopEvent, 0, 10)
-	stopEventsMu      sync.Mutex
-	heartbeatMonitors = sync.Map{}
+	// registeredActionOptions holds the options passed to RegisterAction by action id.
+	registeredActionOptions = make(map[string]actionOptions)
+	statePersister          = state_persister.NewInmemoryStatePersister()
+	stopEvents              = make([]stopEvent, 0, 10)
+	stopEventsMu            sync.Mutex
+	heartbeatMonitors       = sync.Map{}
+	// lastHeartbeats holds the time of the last heartbeat of monitored executions.
+	lastHeartbeats = sync.Map{}
 )
 
 type stopEvent struct {
@@ -82,7 +86,7 @@ func InstallSignalHandler(callbacks ...SignalHandlerCallBackFn) {
 			signalName := extsignals.GetSignalName(s.(syscall.Signal))
 
 			log.Debug().Str("signal", signalName).Msg("received signal - stopping all active actions")
-			StopAllActiveActions(fmt.Sprintf("received signal %s", signalName))
+			stopAllActiveActions(fmt.Sprintf("received signal %s", signalName), s == syscall.SIGUSR1)
 
 			for _, callback := range callbacks {
 				log.Debug().Str("signal", signalName).Msg("calling signal handler callback")
@@ -102,21 +106,36 @@ func InstallSignalHandler(callbacks ...SignalHandlerCallBackFn) {
 	}(signalChannel)
 }
 
-func StopAllActiveActions(reason string) {
-	ctx := context.Background()
-	executionIds, err := statePersister.GetExecutionIds(ctx)
-	if err != nil {
-		log.Error().Err(err).Msgf("Failed to load active action states")
-	}
-	if len(executionIds) > 0 {
-		log.Warn().Str("reason", reason).Msg("stopping active actions")
-	}
-	for _, executionId := range executionIds {
-		StopAction(ctx, executionId, reason)
+// SetStatePersister replaces the persister used to store the state of active actions. The default is an in-memory persister,
+// use [state_persister.NewFileStatePersister] to keep states across restarts of the extension.
+// Must be called before any action is registered.
+func SetStatePersister(persister state_persister.StatePersister) {
+	if persister == nil {
+		log.Fatal().Msg("state persister must not be nil")
 	}
+	statePersister = persister
 }
 
 func StopAction(ctx context.Context, executionId uuid.UUID, reason string) {
+	_ = stopAction(ctx, executionId, reason)
+}
+
+// errActionNotRegistered is returned by stopAction if the persisted state belongs to an action which is not registered.
+var errActionNotRegistered = errors.New("action is not registered")
+
+func stopAction(ctx context.Context, executionId uuid.UUID, reason string) error {
+	// waiting for in-flight calls counts against the deadline of the stop policy, it must not delay reverting the action on top of it
+	if deadline := stopPolicyOf(ctx, executionId).deadline(); deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+	// in-flight calls, e.g. a start still waiting for a sidecar, are canceled and awaited, so that they don't apply the attack after the stop
+	release := inflight.cancel(ctx, executionId, reason)
+	defer release()
+	// the status of the execution is not called anymore, also if stopping it fails
+	defer removeExecutionSink(executionId)
+
 	persistedState, err := statePersister.GetState(ctx, executionId)
 	if err != nil {
 		log.Error().
@@ -124,7 +143,7 @@ func StopAction(ctx context.Context, executionId uuid.UUID, reason string) {
 			Str("executionId", executionId.String()).
 			Str("reason", reason).
 			Msgf("state cannot be loaded, cannot stop active action")
-		return
+		return err
 	}
 
 	action, ok := registeredActions[persistedState.ActionId]
@@ -134,51 +153,101 @@ func StopAction(ctx context.Context, executionId uuid.UUID, reason string) {
 			Str("executionId", persistedState.ExecutionId.String()).
 			Str("reason", reason).
 			Msgf("action is not registered, cannot stop active action")
-		return
+		return errActionNotRegistered
 	}
 
 	actionType := reflect.ValueOf(action)
-	if stopMethod := actionType.MethodByName("Stop"); !stopMethod.IsNil() {
-		rState := actionType.MethodByName("NewEmptyState").Call(nil)[0]
-		state := reflect.New(rState.Type()).Interface()
-
-		if err := extconversion.Convert(persistedState.State, &state); err != nil {
-			log.Error().
-				Str("actionId", persistedState.ActionId).
-				Str("executionId", persistedState.ExecutionId.String()).
-				Str("reason", reason).
-				Err(err).
-				Msg("failed to convert state, cannot stop active action")
-			return
-		}
-
+	stopMethod := actionType.MethodByName("Stop")
+	if !stopMethod.IsValid() {
+		// nothing to revert, e.g. the action implemented Stop in a previous version of the extension
 		log.Info().
 			Str("actionId", persistedState.ActionId).
 			Str("executionId", persistedState.ExecutionId.String()).
 			Str("reason", reason).
-			Msg("stopping active action")
-
-		markAsStopped(persistedState.ExecutionId, reason)
-
-		if err := stopMethod.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(state)})[1].Interface(); err != nil {
-			log.Warn().
-				Str("actionId", persistedState.ActionId).
-				Str("executionId", persistedState.ExecutionId.String()).
-				Str("reason", reason).
-				Err(err.(error)).
-				Msg("failed stopping active action")
-			return
-		}
+			Msg("action has no stop, removing state of active action")
+		deletePersistedState(ctx, persistedState, reason)
+		return nil
+	}
+
+	rState := actionType.MethodByName("NewEmptyState").Call(nil)[0]
+	state := reflect.New(rState.Type()).Interface()
+
+	if err := registeredActionOptions[persistedState.ActionId].decodeState(persistedState.State, &state); err != nil {
+		log.Error().
+			Str("actionId", persistedState.ActionId).
+			Str("executionId", persistedState.ExecutionId.String()).
+			Str("reason", reason).
+			Err(err).
+			Msg("failed to convert state, cannot stop active action")
+		return err
+	}
 
-		stopMonitorHeartbeat(persistedState.ExecutionId)
-		if err := statePersister.DeleteState(ctx, persistedState.ExecutionId); err != nil {
-			log.Debug().
-				Str("actionId", persistedState.ActionId).
-				Str("executionId", persistedState.ExecutionId.String()).
-				Str("reason", reason).
-				Err(err).
-				Msg("failed deleting persisted state")
+	log.Info().
+		Str("actionId", persistedState.ActionId).
+		Str("executionId", persistedState.ExecutionId.String()).
+		Str("reason", reason).
+		Msg("stopping active action")
+	log.Debug().
+		Str("executionId", persistedState.ExecutionId.String()).
+		Interface("state", MaskSecrets(state)).
+		Msg("state of the active action to stop")
+
+	markAsStopped(persistedState.ExecutionId, reason)
+
+	policy := registeredActionOptions[persistedState.ActionId].getStopPolicy()
+	ctx = contextWithExecutionLogger(ctx, persistedState.ActionId, persistedState.ExecutionId, logFieldsFromState(persistedState.State))
+	// tracked, so that other stops by the extension wait for it
+	ctx, done := inflight.track(ctx, persistedState.ExecutionId, PhaseStop)
+	defer done()
+	description, _ := actionType.MethodByName("Describe").Call(nil)[0].Interface().(action_kit_api.ActionDescription)
+	call := &LifecycleCall{
+		Phase:       PhaseStop,
+		ExecutionId: persistedState.ExecutionId,
+		Request:     &action_kit_api.StopActionRequestBody{ExecutionId: persistedState.ExecutionId, State: persistedState.State},
+		Reason:      reason,
+	}
+	// stops by the extension pass the interceptors like the stops requested by the agent
+	stopError := interceptCall(ctx, call, description, registeredActionOptions[persistedState.ActionId], state, "Failed to stop action.", func(ctx context.Context) error {
+		_, err := callStop(ctx, policy, persistedState.ExecutionId, persistedState.ActionId, reason, func(ctx context.Context) (*action_kit_api.StopResult, error) {
+			results := stopMethod.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(state)})
+			result, _ := results[0].Interface().(*action_kit_api.StopResult)
+			err, _ := results[1].Interface().(error)
+			return result, err
+		})
+		return err
+	})
+	audit(ctx, AuditEvent{Type: AuditStoppedByExtension, ActionId: persistedState.ActionId, ExecutionId: persistedState.ExecutionId, Reason: reason, Error: stopError})
+	if stopError != nil {
+		if call.Err != nil {
+			return call.Err
 		}
+		// short-circuited by an interceptor
+		return fromActionKitError(stopError)
+	}
+
+	stopMonitorHeartbeat(persistedState.ExecutionId)
+	removeUploadFolder(persistedState.ExecutionId)
+	deletePersistedState(ctx, persistedState, reason)
+	return nil
+}
+
+// stopPolicyOf returns the stop policy of the execution's action, the DefaultStopPolicy if the state can't be loaded.
+func stopPolicyOf(ctx context.Context, executionId uuid.UUID) StopPolicy {
+	persistedState, err := statePersister.GetState(ctx, executionId)
+	if err != nil {
+		return DefaultStopPolicy
+	}
+	return registeredActionOptions[persistedState.ActionId].getStopPolicy()
+}
+
+func deletePersistedState(ctx context.Context, persistedState *state_persister.PersistedState, reason string) {
+	if err := statePersister.DeleteState(ctx, persistedState.ExecutionId); err != nil {
+		log.Debug().
+			Str("actionId", persistedState.ActionId).
+			Str("executionId", persistedState.ExecutionId.String()).
+			Str("reason", reason).
+			Err(err).
+			Msg("failed deleting persisted state")
 	}
 }
 
@@ -204,7 +273,9 @@ func handleCoverageCounters(w http.ResponseWriter, _ *http.Request, _ []byte) {
 	}
 }
 
-func RegisterAction[T any](a Action[T]) {
+// RegisterAction registers the http handlers of the action. The behaviour of the SDK can be adjusted per action using ActionOption.
+// Executions of the action left behind by a previous process of the extension are stopped, see RecoverActiveActions.
+func RegisterAction[T any](a Action[T], opts ...ActionOption) {
 	//register "StopActions" signal handler with the first registered action
 	if len(registeredActions) == 0 {
 		extsignals.AddSignalHandler(extsignals.SignalHandler{
@@ -212,14 +283,16 @@ func RegisterAction[T any](a Action[T]) {
 				signalName := extsignals.GetSignalName(signal.(syscall.Signal))
 
 				log.Debug().Str("signal", signalName).Msg("received signal - stopping all active actions")
-				StopAllActiveActions(fmt.Sprintf("received signal %s", signalName))
+				stopAllActiveActions(fmt.Sprintf("received signal %s", signalName), signal == syscall.SIGUSR1)
 			},
 			Order: extsignals.OrderStopActions,
 			Name:  "StopActions",
 		})
 	}
-	adapter := newActionHttpAdapter(a)
+	adapter := newActionHttpAdapter(a, opts...)
 	registeredActions[adapter.description.Id] = a
+	registeredActionOptions[adapter.description.Id] = adapter.options
+	recoverActiveActionsOf(context.Background(), adapter.description.Id)
 	adapter.registerHandlers()
 	exthttp.BumpRevision()
 }
@@ -227,6 +300,7 @@ func RegisterAction[T any](a Action[T]) {
 // ClearRegisteredActions clears all registered actions - used for testing. Warning: This will not remove the registered routes from the http server.
 func ClearRegisteredActions() {
 	registeredActions = make(map[string]any)
+	registeredActionOptions = make(map[string]actionOptions)
 	exthttp.BumpRevision()
 }
 
@@ -245,8 +319,12 @@ func GetActionList() action_kit_api.ActionList {
 	}
 }
 
-func monitorHeartbeat(executionId uuid.UUID, interval, timeout time.Duration) {
+func monitorHeartbeat(executionId uuid.UUID, interval, timeout time.Duration, onTimeout func(executionId uuid.UUID)) {
 	monitorHeartbeatWithCallback(executionId, interval, timeout, func() {
+		audit(context.Background(), AuditEvent{Type: AuditHeartbeatTimeout, ExecutionId: executionId})
+		if onTimeout != nil {
+			onTimeout(executionId)
+		}
 		StopAction(context.Background(), executionId, "heartbeat timeout")
 	})
 }
@@ -274,6 +352,7 @@ func recordHeartbeat(executionId uuid.UUID) {
 	monitor, _ := heartbeatMonitors.Load(executionId)
 	if monitor != nil {
 		monitor.(*extheartbeat.Monitor).RecordHeartbeat()
+		lastHeartbeats.Store(executionId, time.Now())
 	}
 }
 
@@ -284,6 +363,7 @@ func stopMonitorHeartbeat(executionId uuid.UUID) {
 	if monitor, ok := heartbeatMonitors.LoadAndDelete(executionId); ok {
 		monitor.(*extheartbeat.Monitor).Stop()
 	}
+	lastHeartbeats.Delete(executionId)
 }
 
 func markAsStopped(executionId uuid.UUID, reason string) {
@@ -62,6 +62,13 @@ func describe(t *testing.T, actionPath string) action_kit_api.ActionDescription
 }
 
 func (op *ActionOperations) prepare(t *testing.T) (*action_kit_api.PrepareResult, *action_kit_api.ActionKitError) {
+	return op.prepareWithConfig(t, map[string]any{
+		"duration":  "10s",
+		"inputFile": "file::1234567890",
+	})
+}
+
+func (op *ActionOperations) prepareWithConfig(t *testing.T, config map[string]any) (*action_kit_api.PrepareResult, *action_kit_api.ActionKitError) {
 	prepareBody := action_kit_api.PrepareActionRequestBody{
 		ExecutionId: op.executionId,
 		Target: &action_kit_api.Target{
@@ -71,9 +78,7 @@ func (op *ActionOperations) prepare(t *testing.T) (*action_kit_api.PrepareResult
 				"k8s.cluster-name": {"minikube"},
 			},
 		},
-		Config: map[string]any{
-			"duration": "10s",
-		},
+		Config: config,
 	}
 
 	jsonBody, err := json.Marshal(prepareBody)
@@ -70,6 +70,10 @@ func Test_SDK(t *testing.T) {
 			Name: "should return extension status from prepare and update state",
 			Fn:   testCaseStatusWithExtensionKitErrorAndStateUpdate,
 		},
+		{
+			Name: "should reject invalid configuration before prepare",
+			Fn:   testCasePrepareWithInvalidConfig,
+		},
 	}
 	calls := make(chan Call, 1024)
 	defer close(calls)
@@ -257,3 +261,15 @@ func testCaseStatusWithExtensionKitErrorAndStateUpdate(t *testing.T, op ActionOp
 	assert.Equal(t, "StatusBeforeError", (*startResult.State)["TestStep"].(string))
 	op.assertCall(t, "Status", ANY_ARG)
 }
+
+func testCasePrepareWithInvalidConfig(t *testing.T, op ActionOperations) {
+	prepareResult, actionError := op.prepareWithConfig(t, map[string]any{
+		"duration": "ten seconds",
+	})
+	assert.Nil(t, actionError)
+	require.NotNil(t, prepareResult.Error)
+	assert.Equal(t, "Invalid action configuration.", prepareResult.Error.Title)
+	assert.Contains(t, *prepareResult.Error.Detail, "duration: is not a valid duration: \"ten seconds\"")
+	assert.Contains(t, *prepareResult.Error.Detail, "inputFile: is required")
+	assert.Empty(t, op.calls, "Prepare must not be called")
+}
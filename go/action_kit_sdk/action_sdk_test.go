@@ -2,6 +2,7 @@ package action_kit_sdk
 
 import (
 	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
 	"github.com/stretchr/testify/assert"
 	"runtime"
 	"sync"
@@ -70,3 +71,13 @@ func TestHeartbeat_should_not_timeout(t *testing.T) {
 		close(stop)
 	}
 }
+
+func TestSetStatePersister(t *testing.T) {
+	previous := statePersister
+	t.Cleanup(func() { statePersister = previous })
+
+	persister, err := state_persister.NewFileStatePersister(t.TempDir())
+	assert.NoError(t, err)
+	SetStatePersister(persister)
+	assert.Same(t, persister, statePersister)
+}
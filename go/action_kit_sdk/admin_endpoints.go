@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/exthttp"
+)
+
+const (
+	adminExecutionsPath = "/admin/executions"
+	adminStopReason     = "stopped by admin"
+)
+
+// ActiveExecution describes an active execution as returned by the admin endpoints.
+type ActiveExecution struct {
+	ExecutionId uuid.UUID              `json:"executionId"`
+	ActionId    string                 `json:"actionId"`
+	Target      *action_kit_api.Target `json:"target,omitempty"`
+	StartedAt   *time.Time             `json:"startedAt,omitempty"`
+	Heartbeat   HeartbeatStatus        `json:"heartbeat"`
+	// StoppedBy is the reason if the execution was stopped by the extension and the agent has not yet called stop.
+	StoppedBy *string `json:"stoppedBy,omitempty"`
+}
+
+// HeartbeatStatus describes the heartbeat monitoring of an active execution.
+type HeartbeatStatus struct {
+	Monitored     bool       `json:"monitored"`
+	LastHeartbeat *time.Time `json:"lastHeartbeat,omitempty"`
+}
+
+// ActiveExecutionWithState is an active execution including its persisted state.
+type ActiveExecutionWithState struct {
+	ActiveExecution
+	State action_kit_api.ActionState `json:"state"`
+}
+
+// AdminStopRequest is the body of the force-stop endpoint.
+type AdminStopRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RegisterAdminEndpoints registers endpoints to inspect and stop active executions. All requests must carry the token as bearer token.
+//   - GET /admin/executions lists the active executions.
+//   - GET /admin/executions/{executionId} returns an execution including its state.
+//   - POST /admin/executions/{executionId}/stop stops an execution, the reason is taken from an optional AdminStopRequest body.
+func RegisterAdminEndpoints(token string) {
+	if token == "" {
+		log.Fatal().Msg("admin endpoints require a token")
+	}
+	exthttp.RegisterHttpHandler("GET "+adminExecutionsPath, requireBearerToken(token, handleListExecutions))
+	exthttp.RegisterHttpHandler("GET "+adminExecutionsPath+"/{executionId}", requireBearerToken(token, handleGetExecution))
+	exthttp.RegisterHttpHandler("POST "+adminExecutionsPath+"/{executionId}/stop", requireBearerToken(token, handleStopExecution))
+}
+
+func requireBearerToken(token string, next exthttp.Handler) exthttp.Handler {
+	return func(w http.ResponseWriter, r *http.Request, body []byte) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeErrorWithStatus(w, http.StatusUnauthorized, extension_kit.ToError("Unauthorized.", nil))
+			return
+		}
+		next(w, r, body)
+	}
+}
+
+func handleListExecutions(w http.ResponseWriter, r *http.Request, _ []byte) {
+	executionIds, err := statePersister.GetExecutionIds(r.Context())
+	if err != nil {
+		exthttp.WriteError(w, extension_kit.ToError("Failed to load active executions.", err))
+		return
+	}
+
+	executions := make([]ActiveExecution, 0, len(executionIds))
+	for _, executionId := range executionIds {
+		persisted, err := statePersister.GetState(r.Context(), executionId)
+		if err != nil {
+			// deleted in the meantime
+			continue
+		}
+		executions = append(executions, toActiveExecution(persisted))
+	}
+	exthttp.WriteBody(w, executions)
+}
+
+func handleGetExecution(w http.ResponseWriter, r *http.Request, _ []byte) {
+	persisted := getPersistedExecution(w, r)
+	if persisted == nil {
+		return
+	}
+	exthttp.WriteBody(w, ActiveExecutionWithState{
+		ActiveExecution: toActiveExecution(persisted),
+		State:           persisted.State,
+	})
+}
+
+func handleStopExecution(w http.ResponseWriter, r *http.Request, body []byte) {
+	persisted := getPersistedExecution(w, r)
+	if persisted == nil {
+		return
+	}
+
+	request := AdminStopRequest{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &request); err != nil {
+			writeErrorWithStatus(w, http.StatusBadRequest, extension_kit.ToError("Failed to parse request body.", err))
+			return
+		}
+	}
+	reason := adminStopReason
+	if request.Reason != "" {
+		reason = fmt.Sprintf("%s: %s", adminStopReason, request.Reason)
+	}
+
+	log.Warn().
+		Str("actionId", persisted.ActionId).
+		Str("executionId", persisted.ExecutionId.String()).
+		Str("reason", reason).
+		Msg("force-stopping execution")
+	if err := stopAction(r.Context(), persisted.ExecutionId, reason); err != nil {
+		exthttp.WriteError(w, extension_kit.ToError("Failed to stop execution.", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getPersistedExecution(w http.ResponseWriter, r *http.Request) *state_persister.PersistedState {
+	executionId, err := uuid.Parse(r.PathValue("executionId"))
+	if err != nil {
+		writeErrorWithStatus(w, http.StatusBadRequest, extension_kit.ToError("Invalid execution id.", err))
+		return nil
+	}
+	persisted, err := statePersister.GetState(r.Context(), executionId)
+	if err != nil {
+		writeErrorWithStatus(w, http.StatusNotFound, extension_kit.ToError("Execution not found.", err))
+		return nil
+	}
+	return persisted
+}
+
+func toActiveExecution(persisted *state_persister.PersistedState) ActiveExecution {
+	execution := ActiveExecution{
+		ExecutionId: persisted.ExecutionId,
+		ActionId:    persisted.ActionId,
+		Target:      persisted.Target,
+		StartedAt:   persisted.StartedAt,
+	}
+	if _, monitored := heartbeatMonitors.Load(persisted.ExecutionId); monitored {
+		execution.Heartbeat.Monitored = true
+	}
+	if lastHeartbeat, ok := lastHeartbeats.Load(persisted.ExecutionId); ok {
+		execution.Heartbeat.LastHeartbeat = new(lastHeartbeat.(time.Time))
+	}
+	if stopEvent := getStopEvent(persisted.ExecutionId); stopEvent != nil {
+		execution.StoppedBy = new(stopEvent.reason)
+	}
+	return execution
+}
+
+func writeErrorWithStatus(w http.ResponseWriter, status int, err extension_kit.ExtensionError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if encodeErr := json.NewEncoder(w).Encode(err); encodeErr != nil {
+		log.Err(encodeErr).Msgf("Failed to write error response.")
+	}
+}
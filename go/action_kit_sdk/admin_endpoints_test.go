@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	handler := requireBearerToken("secret", func(w http.ResponseWriter, _ *http.Request, _ []byte) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for header, status := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusNoContent,
+	} {
+		r := httptest.NewRequest("GET", adminExecutionsPath, nil)
+		r.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+		handler(w, r, nil)
+		assert.Equal(t, status, w.Code, header)
+	}
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	action := NewExampleAction(calls)
+	actionId := action.Describe().Id
+	registeredActions[actionId] = action
+	t.Cleanup(func() { delete(registeredActions, actionId) })
+
+	executionId := uuid.New()
+	startedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{
+		ExecutionId: executionId,
+		ActionId:    actionId,
+		State:       action_kit_api.ActionState{"TestStep": "Start"},
+		Target:      containerTarget("c1"),
+		StartedAt:   &startedAt,
+	}))
+	monitorHeartbeat(executionId, time.Minute, time.Hour, nil)
+	defer stopMonitorHeartbeat(executionId)
+	recordHeartbeat(executionId)
+
+	w := httptest.NewRecorder()
+	handleListExecutions(w, httptest.NewRequest("GET", adminExecutionsPath, nil), nil)
+	var executions []ActiveExecution
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &executions))
+	require.Len(t, executions, 1)
+	assert.Equal(t, executionId, executions[0].ExecutionId)
+	assert.Equal(t, actionId, executions[0].ActionId)
+	assert.True(t, startedAt.Equal(*executions[0].StartedAt))
+	assert.True(t, executions[0].Heartbeat.Monitored)
+	assert.NotNil(t, executions[0].Heartbeat.LastHeartbeat)
+
+	r := httptest.NewRequest("GET", adminExecutionsPath+"/"+executionId.String(), nil)
+	r.SetPathValue("executionId", executionId.String())
+	w = httptest.NewRecorder()
+	handleGetExecution(w, r, nil)
+	var execution ActiveExecutionWithState
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &execution))
+	assert.Equal(t, "Start", execution.State["TestStep"])
+	assert.Equal(t, "c1", execution.Target.Name)
+
+	r = httptest.NewRequest("POST", adminExecutionsPath+"/"+executionId.String()+"/stop", nil)
+	r.SetPathValue("executionId", executionId.String())
+	w = httptest.NewRecorder()
+	handleStopExecution(w, r, []byte(`{"reason":"stuck experiment"}`))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "Stop", (<-calls).Name)
+	require.NotNil(t, getStopEvent(executionId))
+	assert.Equal(t, "stopped by admin: stuck experiment", getStopEvent(executionId).reason)
+
+	w = httptest.NewRecorder()
+	handleGetExecution(w, r, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
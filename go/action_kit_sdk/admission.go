@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// reserveAdmission checks the admission of the execution and reserves its slot until release is called, i.e. until its state is persisted.
+// The lock is only held for the check, so that prepares of other executions are not blocked by a slow Prepare.
+func (a *actionHttpAdapter[T]) reserveAdmission(ctx context.Context, executionId uuid.UUID, target *action_kit_api.Target) (release func(), rejection *action_kit_api.ActionKitError) {
+	a.admissionMu.Lock()
+	defer a.admissionMu.Unlock()
+	if rejection := checkAdmission(ctx, a.description.Id, a.options, executionId, target, a.reservations); rejection != nil {
+		return nil, rejection
+	}
+	if a.reservations == nil {
+		a.reservations = make(map[uuid.UUID]*action_kit_api.Target)
+	}
+	a.reservations[executionId] = target
+	return func() {
+		a.admissionMu.Lock()
+		defer a.admissionMu.Unlock()
+		delete(a.reservations, executionId)
+	}, nil
+}
+
+// checkAdmission verifies the concurrency limits of the action against the other started and reserved executions.
+// Executions which were only prepared don't take a slot, they might never be started. Neither do leftovers, which the extension failed to revert.
+// Returns nil if the execution may proceed.
+func checkAdmission(ctx context.Context, actionId string, options actionOptions, executionId uuid.UUID, target *action_kit_api.Target, reserved map[uuid.UUID]*action_kit_api.Target) *action_kit_api.ActionKitError {
+	if !options.hasAdmissionControl() {
+		return nil
+	}
+
+	executionIds, err := statePersister.GetExecutionIds(ctx)
+	if err != nil {
+		return &action_kit_api.ActionKitError{
+			Title:  "Failed to load active executions.",
+			Detail: new(err.Error()),
+		}
+	}
+
+	var active []uuid.UUID
+	targets := make(map[uuid.UUID]*action_kit_api.Target)
+	for _, otherId := range executionIds {
+		if otherId == executionId {
+			continue
+		}
+		other, err := statePersister.GetState(ctx, otherId)
+		if err != nil || other.ActionId != actionId || other.StartedAt == nil || other.Leftover != nil {
+			continue
+		}
+		active = append(active, otherId)
+		targets[otherId] = other.Target
+	}
+	for otherId, otherTarget := range reserved {
+		if _, persisted := targets[otherId]; persisted || otherId == executionId {
+			continue
+		}
+		active = append(active, otherId)
+		targets[otherId] = otherTarget
+	}
+
+	var conflicts []string
+	for _, otherId := range active {
+		otherTarget := targets[otherId]
+		if target == nil || otherTarget == nil {
+			continue
+		}
+		for _, key := range options.exclusiveTargetAttributes {
+			for _, value := range target.Attributes[key] {
+				if slices.Contains(otherTarget.Attributes[key], value) {
+					conflicts = append(conflicts, fmt.Sprintf("%s=%s is used by execution %s", key, value, otherId))
+				}
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		log.Info().
+			Str("actionId", actionId).
+			Str("executionId", executionId.String()).
+			Strs("conflicts", conflicts).
+			Msg("rejecting execution, target is already used by another execution")
+		return &action_kit_api.ActionKitError{
+			Title:  "The target is already attacked by another execution of this action.",
+			Detail: new(strings.Join(conflicts, "\n")),
+		}
+	}
+
+	if options.maxConcurrentExecutions > 0 && len(active) >= options.maxConcurrentExecutions {
+		log.Info().
+			Str("actionId", actionId).
+			Str("executionId", executionId.String()).
+			Int("limit", options.maxConcurrentExecutions).
+			Msg("rejecting execution, maximum of concurrent executions reached")
+		return &action_kit_api.ActionKitError{
+			Title:  fmt.Sprintf("Maximum of %d concurrent execution(s) of this action reached.", options.maxConcurrentExecutions),
+			Detail: new(fmt.Sprintf("Active executions: %s", joinIds(active))),
+		}
+	}
+	return nil
+}
+
+func joinIds(ids []uuid.UUID) string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, id.String())
+	}
+	return strings.Join(s, ", ")
+}
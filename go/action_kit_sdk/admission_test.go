@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useInmemoryStatePersister(t *testing.T) {
+	previous := statePersister
+	t.Cleanup(func() { statePersister = previous })
+	statePersister = state_persister.NewInmemoryStatePersister()
+}
+
+func containerTarget(id string) *action_kit_api.Target {
+	return &action_kit_api.Target{Name: id, Attributes: map[string][]string{"container.id": {id}}}
+}
+
+func TestCheckAdmission_exclusive_target_attribute(t *testing.T) {
+	useInmemoryStatePersister(t)
+	options := newActionOptions(WithExclusiveTargetAttribute("container.id"))
+	active := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: active, ActionId: "stress", Target: containerTarget("c1"), StartedAt: new(time.Now())}))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: uuid.New(), ActionId: "other-action", Target: containerTarget("c2"), StartedAt: new(time.Now())}))
+
+	rejection := checkAdmission(context.Background(), "stress", options, uuid.New(), containerTarget("c1"), nil)
+	require.NotNil(t, rejection)
+	assert.Equal(t, "The target is already attacked by another execution of this action.", rejection.Title)
+	assert.Equal(t, "container.id=c1 is used by execution "+active.String(), *rejection.Detail)
+
+	assert.Nil(t, checkAdmission(context.Background(), "stress", options, uuid.New(), containerTarget("c2"), nil))
+	assert.Nil(t, checkAdmission(context.Background(), "stress", options, active, containerTarget("c1"), nil), "an execution must not conflict with itself")
+}
+
+func TestCheckAdmission_max_concurrent_executions(t *testing.T) {
+	useInmemoryStatePersister(t)
+	options := newActionOptions(WithMaxConcurrentExecutions(2))
+	first, second := uuid.New(), uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: first, ActionId: "stress", StartedAt: new(time.Now())}))
+
+	assert.Nil(t, checkAdmission(context.Background(), "stress", options, second, nil, nil))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: second, ActionId: "stress", StartedAt: new(time.Now())}))
+
+	rejection := checkAdmission(context.Background(), "stress", options, uuid.New(), nil, nil)
+	require.NotNil(t, rejection)
+	assert.Equal(t, "Maximum of 2 concurrent execution(s) of this action reached.", rejection.Title)
+	assert.Nil(t, checkAdmission(context.Background(), "stress", options, second, nil, nil))
+}
+
+func TestCheckAdmission_ignores_prepared_executions_and_leftovers(t *testing.T) {
+	useInmemoryStatePersister(t)
+	options := newActionOptions(WithMaxConcurrentExecutions(1), WithExclusiveTargetAttribute("container.id"))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: uuid.New(), ActionId: "stress", Target: containerTarget("c1")}))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{
+		ExecutionId: uuid.New(), ActionId: "stress", Target: containerTarget("c1"), StartedAt: new(time.Now()),
+		Leftover: &state_persister.Leftover{Reason: "heartbeat timeout", Error: "tc failed", Timestamp: time.Now()},
+	}))
+
+	assert.Nil(t, checkAdmission(context.Background(), "stress", options, uuid.New(), containerTarget("c1"), nil))
+}
+
+func TestHandlePrepare_rejects_conflicting_execution(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(calls), WithExclusiveTargetAttribute("container.id"), WithoutParameterValidation())
+
+	prepare := func(executionId uuid.UUID) action_kit_api.PrepareResult {
+		body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: executionId, Target: containerTarget("c1"), Config: map[string]any{}})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+		var result action_kit_api.PrepareResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	first := uuid.New()
+	prepared := prepare(first)
+	require.Nil(t, prepared.Error)
+	assert.Equal(t, "Prepare", (<-calls).Name)
+	assert.Nil(t, prepare(uuid.New()).Error, "prepared executions don't take a slot")
+	assert.Equal(t, "Prepare", (<-calls).Name)
+
+	body, err := json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: first, State: prepared.State})
+	require.NoError(t, err)
+	adapter.handleStart(httptest.NewRecorder(), httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	assert.Equal(t, "Start", (<-calls).Name)
+
+	rejected := prepare(uuid.New())
+	require.NotNil(t, rejected.Error)
+	assert.Equal(t, "The target is already attacked by another execution of this action.", rejected.Error.Title)
+	assert.Empty(t, calls, "Prepare must not be called for rejected executions")
+}
+
+func TestReserveAdmission(t *testing.T) {
+	useInmemoryStatePersister(t)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)), WithMaxConcurrentExecutions(1), WithExclusiveTargetAttribute("container.id"))
+	first, second := uuid.New(), uuid.New()
+
+	release, rejection := adapter.reserveAdmission(context.Background(), first, containerTarget("c1"))
+	require.Nil(t, rejection)
+	_, rejection = adapter.reserveAdmission(context.Background(), second, containerTarget("c1"))
+	require.NotNil(t, rejection)
+	assert.Equal(t, "container.id=c1 is used by execution "+first.String(), *rejection.Detail)
+
+	release()
+	_, rejection = adapter.reserveAdmission(context.Background(), second, containerTarget("c1"))
+	assert.Nil(t, rejection)
+}
+
+func TestHandlePrepare_failed_prepare_releases_admission(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	action := NewExampleAction(calls)
+	adapter := newActionHttpAdapter[ExampleState](action, WithMaxConcurrentExecutions(1), WithoutParameterValidation())
+
+	prepare := func() action_kit_api.PrepareResult {
+		body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New(), Target: containerTarget("c1"), Config: map[string]any{}})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+		var result action_kit_api.PrepareResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	action.prepareError = errors.New("prepare failed")
+	require.NotNil(t, prepare().Error)
+	executionIds, err := statePersister.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, executionIds, "the state of a failed prepare must not be persisted")
+
+	action.prepareError = nil
+	assert.Nil(t, prepare().Error)
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+)
+
+// AuditEventType is the lifecycle transition recorded by an AuditEvent.
+type AuditEventType string
+
+const (
+	AuditPrepared           AuditEventType = "prepared"
+	AuditPrepareRejected    AuditEventType = "prepare-rejected"
+	AuditStarted            AuditEventType = "started"
+	AuditStatusCompleted    AuditEventType = "status-completed"
+	AuditStopped            AuditEventType = "stopped"
+	AuditStoppedByExtension AuditEventType = "stopped-by-extension"
+	AuditHeartbeatTimeout   AuditEventType = "heartbeat-timeout"
+)
+
+// Reasons of AuditPrepareRejected events.
+const (
+	rejectedByValidation  = "invalid configuration"
+	rejectedByAdmission   = "admission control"
+	rejectedByInterceptor = "interceptor"
+)
+
+// AuditEvent is written as one JSON line per lifecycle transition of an execution.
+type AuditEvent struct {
+	Timestamp        time.Time           `json:"timestamp"`
+	Type             AuditEventType      `json:"type"`
+	ActionId         string              `json:"actionId"`
+	ExecutionId      uuid.UUID           `json:"executionId"`
+	TargetName       string              `json:"targetName,omitempty"`
+	TargetAttributes map[string][]string `json:"targetAttributes,omitempty"`
+	ExperimentKey    *string             `json:"experimentKey,omitempty"`
+	// ExperimentExecutionId is the id of the experiment execution in the platform.
+	ExperimentExecutionId *int                           `json:"experimentExecutionId,omitempty"`
+	ExecutionUri          *string                        `json:"executionUri,omitempty"`
+	AgentPid              *int                           `json:"agentPid,omitempty"`
+	Reason                string                         `json:"reason,omitempty"`
+	Error                 *action_kit_api.ActionKitError `json:"error,omitempty"`
+}
+
+var (
+	auditMu      sync.Mutex
+	auditEncoder *json.Encoder
+)
+
+// SetAuditWriter enables the audit log. Each lifecycle transition is written as one JSON line to the writer,
+// e.g. os.Stdout or a RotatingFileWriter. Pass nil to disable the audit log.
+func SetAuditWriter(writer io.Writer) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if writer == nil {
+		auditEncoder = nil
+		return
+	}
+	auditEncoder = json.NewEncoder(writer)
+}
+
+func isAuditEnabled() bool {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	return auditEncoder != nil
+}
+
+// auditPrepare records the outcome of the prepare request. Rejected prepares carry the reason of the rejection.
+func (a *actionHttpAdapter[T]) auditPrepare(ctx context.Context, request *action_kit_api.PrepareActionRequestBody, eventType AuditEventType, reason string, err *action_kit_api.ActionKitError) {
+	event := AuditEvent{Type: eventType, ActionId: a.description.Id, ExecutionId: request.ExecutionId, Reason: reason, Error: err}
+	event.withTarget(request.Target)
+	event.withExecutionContext(request.ExecutionContext)
+	audit(ctx, event)
+}
+
+// audit writes the event. Target and execution context are taken from the persisted state, if not already set.
+func audit(ctx context.Context, event AuditEvent) {
+	if !isAuditEnabled() {
+		return
+	}
+	if event.TargetName == "" && event.ExperimentKey == nil {
+		if persisted, err := statePersister.GetState(ctx, event.ExecutionId); err == nil {
+			event.withPersistedState(persisted)
+		}
+	}
+	event.Timestamp = time.Now()
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditEncoder == nil {
+		return
+	}
+	if err := auditEncoder.Encode(event); err != nil {
+		log.Error().Err(err).
+			Str("actionId", event.ActionId).
+			Str("executionId", event.ExecutionId.String()).
+			Msgf("failed to write audit event %s", event.Type)
+	}
+}
+
+func (e *AuditEvent) withPersistedState(persisted *state_persister.PersistedState) {
+	if e.ActionId == "" {
+		e.ActionId = persisted.ActionId
+	}
+	e.withTarget(persisted.Target)
+	e.withExecutionContext(persisted.ExecutionContext)
+}
+
+func (e *AuditEvent) withTarget(target *action_kit_api.Target) {
+	if target == nil {
+		return
+	}
+	e.TargetName = target.Name
+	e.TargetAttributes = target.Attributes
+}
+
+func (e *AuditEvent) withExecutionContext(executionContext *action_kit_api.ExecutionContext) {
+	if executionContext == nil {
+		return
+	}
+	e.ExperimentKey = executionContext.ExperimentKey
+	e.ExperimentExecutionId = executionContext.ExecutionId
+	e.ExecutionUri = executionContext.ExecutionUri
+	e.AgentPid = executionContext.AgentPid
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useAuditBuffer(t *testing.T) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	SetAuditWriter(buffer)
+	t.Cleanup(func() { SetAuditWriter(nil) })
+	return buffer
+}
+
+func readAuditEvents(t *testing.T, buffer *bytes.Buffer) []AuditEvent {
+	var events []AuditEvent
+	scanner := bufio.NewScanner(buffer)
+	for scanner.Scan() {
+		var event AuditEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestAudit_lifecycle_transitions(t *testing.T) {
+	useInmemoryStatePersister(t)
+	buffer := useAuditBuffer(t)
+	action := NewExampleAction(make(chan Call, 10))
+	adapter := newActionHttpAdapter[ExampleState](action, WithoutParameterValidation())
+	registeredActions[adapter.description.Id] = action
+	t.Cleanup(func() { delete(registeredActions, adapter.description.Id) })
+	executionId := uuid.New()
+
+	post := func(handler func(w *httptest.ResponseRecorder, body []byte), request any, result any) {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		handler(w, body)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), result))
+	}
+
+	var prepareResult action_kit_api.PrepareResult
+	post(func(w *httptest.ResponseRecorder, body []byte) {
+		adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+	}, action_kit_api.PrepareActionRequestBody{
+		ExecutionId: executionId,
+		Target:      containerTarget("c1"),
+		Config:      map[string]any{},
+		ExecutionContext: &action_kit_api.ExecutionContext{
+			ExperimentKey: new("ADM-1"),
+			ExecutionUri:  new("https://platform/executions/42"),
+			AgentPid:      new(4711),
+		},
+	}, &prepareResult)
+
+	var startResult action_kit_api.StartResult
+	post(func(w *httptest.ResponseRecorder, body []byte) {
+		adapter.handleStart(w, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	}, action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: prepareResult.State}, &startResult)
+
+	StopAction(context.Background(), executionId, "test")
+
+	events := readAuditEvents(t, buffer)
+	require.Len(t, events, 3)
+	assert.Equal(t, []AuditEventType{AuditPrepared, AuditStarted, AuditStoppedByExtension}, []AuditEventType{events[0].Type, events[1].Type, events[2].Type})
+	for _, event := range events {
+		assert.Equal(t, adapter.description.Id, event.ActionId)
+		assert.Equal(t, executionId, event.ExecutionId)
+		assert.Equal(t, "c1", event.TargetName)
+		assert.Equal(t, []string{"c1"}, event.TargetAttributes["container.id"])
+		assert.Equal(t, "ADM-1", *event.ExperimentKey)
+		assert.Equal(t, "https://platform/executions/42", *event.ExecutionUri)
+		assert.Equal(t, 4711, *event.AgentPid)
+		assert.Nil(t, event.Error)
+		assert.False(t, event.Timestamp.IsZero())
+	}
+	assert.Equal(t, "test", events[2].Reason)
+}
+
+func TestAudit_disabled_by_default(t *testing.T) {
+	assert.False(t, isAuditEnabled())
+	audit(context.Background(), AuditEvent{Type: AuditStarted})
+}
+
+func TestRotatingFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writer, err := NewRotatingFileWriter(path, 10, 2)
+	require.NoError(t, err)
+	defer func() { _ = writer.Close() }()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := writer.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	for file, content := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		actual, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(actual), file)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "only maxBackups files are kept")
+}
+
+func TestRotatingFileWriter_reopens_file_if_rotation_fails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writer, err := NewRotatingFileWriter(path, 10, 1)
+	require.NoError(t, err)
+	defer func() { _ = writer.Close() }()
+
+	// a non-empty directory can't be replaced by the rotated file
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "blocked"), 0755))
+	_, err = writer.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("second\n"))
+	assert.Error(t, err)
+
+	require.NoError(t, os.RemoveAll(path+".1"))
+	_, err = writer.Write([]byte("second\n"))
+	require.NoError(t, err, "the rotation is tried again with the next write")
+	actual, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(actual))
+}
+
+func TestAudit_rejected_prepares(t *testing.T) {
+	useInmemoryStatePersister(t)
+	buffer := useAuditBuffer(t)
+	rejectingInterceptor := func(_ context.Context, _ *LifecycleCall, _ LifecycleNext) *action_kit_api.ActionKitError {
+		return &action_kit_api.ActionKitError{Title: "Maintenance window."}
+	}
+
+	prepare := func(adapter *actionHttpAdapter[ExampleState]) {
+		body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New(), Target: containerTarget("c1"), Config: map[string]any{}})
+		require.NoError(t, err)
+		adapter.handlePrepare(httptest.NewRecorder(), httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+	}
+	prepare(newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10))))
+	admission := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)), WithoutParameterValidation(), WithExclusiveTargetAttribute("container.id"))
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: uuid.New(), ActionId: admission.description.Id, Target: containerTarget("c1"), StartedAt: new(time.Now())}))
+	prepare(admission)
+	prepare(newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)), WithoutParameterValidation(), WithInterceptor(rejectingInterceptor)))
+
+	events := readAuditEvents(t, buffer)
+	require.Len(t, events, 3)
+	assert.Equal(t, []AuditEventType{AuditPrepareRejected, AuditPrepareRejected, AuditPrepareRejected}, []AuditEventType{events[0].Type, events[1].Type, events[2].Type})
+	assert.Equal(t, []string{"invalid configuration", "admission control", "interceptor"}, []string{events[0].Reason, events[1].Reason, events[2].Reason})
+	assert.Equal(t, "Invalid action configuration.", events[0].Error.Title)
+	assert.Equal(t, "The target is already attacked by another execution of this action.", events[1].Error.Title)
+	assert.Equal(t, "Maintenance window.", events[2].Error.Title)
+	for _, event := range events {
+		assert.Equal(t, "c1", event.TargetName)
+	}
+}
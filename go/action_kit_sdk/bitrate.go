@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bitrate is a data rate in bits per second, as configured by parameters of type [action_kit_api.ActionParameterTypeBitrate].
+type Bitrate uint64
+
+var bitrateExpression = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-z]*)$`)
+
+// bitrateUnits maps the units understood by tc to their factor in bits per second.
+var bitrateUnits = map[string]float64{
+	"":      1,
+	"bit":   1,
+	"kbit":  1e3,
+	"mbit":  1e6,
+	"gbit":  1e9,
+	"tbit":  1e12,
+	"kibit": 1 << 10,
+	"mibit": 1 << 20,
+	"gibit": 1 << 30,
+	"tibit": 1 << 40,
+	"bps":   8,
+	"kbps":  8e3,
+	"mbps":  8e6,
+	"gbps":  8e9,
+	"tbps":  8e12,
+	"kibps": 8 << 10,
+	"mibps": 8 << 20,
+	"gibps": 8 << 30,
+	"tibps": 8 << 40,
+}
+
+// ParseBitrate parses a bitrate like "1024kbit" or "10mbps" using the units of tc (e.g. kbit = 1000 bits per second, kbps = 1000 bytes per second).
+func ParseBitrate(s string) (Bitrate, error) {
+	matches := bitrateExpression.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid bitrate %q", s)
+	}
+	factor, ok := bitrateUnits[matches[2]]
+	if !ok {
+		return 0, fmt.Errorf("invalid bitrate %q: unknown unit %q", s, matches[2])
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bitrate %q: %w", s, err)
+	}
+	return Bitrate(value * factor), nil
+}
+
+// BitsPerSecond returns the bitrate in bits per second.
+func (b Bitrate) BitsPerSecond() uint64 {
+	return uint64(b)
+}
+
+// BytesPerSecond returns the bitrate in bytes per second (rounded down).
+func (b Bitrate) BytesPerSecond() uint64 {
+	return uint64(b) / 8
+}
+
+// String returns the bitrate in a format understood by tc.
+func (b Bitrate) String() string {
+	return fmt.Sprintf("%dbit", uint64(b))
+}
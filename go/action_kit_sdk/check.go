@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	extension_kit "github.com/steadybit/extension-kit"
+)
+
+// ProbeResult is the outcome of a single probe of a check.
+type ProbeResult struct {
+	Success bool
+	// Latency of the probe, emitted as metric and compared with CheckExpectations.MaxLatency.
+	Latency time.Duration
+	// Message is shown in the tooltip of the state over time widget, e.g. "HTTP 503".
+	Message string
+}
+
+// CheckExpectations are evaluated over all probes of a check execution. Zero values disable an expectation.
+type CheckExpectations struct {
+	// MinSuccessRatio is the minimum ratio of successful probes between 0 and 1.
+	MinSuccessRatio float64
+	// MaxLatency is the maximum latency of each probe.
+	MaxLatency time.Duration
+	// SuccessWithin requires a successful probe within this duration after the start, e.g. for a rollout to become ready.
+	SuccessWithin time.Duration
+}
+
+// CheckState is the state of actions created with NewCheckAction. Config holds the configuration of the probe.
+type CheckState[C any] struct {
+	ExecutionId  uuid.UUID
+	Label        string
+	Config       C
+	Expectations CheckExpectations
+	// Duration of the check, the probe is called on every status call until it is over.
+	Duration       time.Duration
+	StartedAt      *time.Time `json:",omitempty"`
+	Probes         int
+	Successes      int
+	SlowProbes     int
+	FirstSuccessAt *time.Time `json:",omitempty"`
+}
+
+// CheckDefinition defines a check action created with NewCheckAction.
+type CheckDefinition[C any] struct {
+	// Description of the action. Kind and time control are set to check and internal.
+	Description action_kit_api.ActionDescription
+	// Prepare sets Config, Expectations and Duration of the state based on the request.
+	Prepare func(ctx context.Context, state *CheckState[C], request action_kit_api.PrepareActionRequestBody) error
+	// Probe is called once per status call. Unsuccessful probes count against the expectations,
+	// an error means the probe could not be run at all and completes the check as errored.
+	Probe func(ctx context.Context, config *C) (ProbeResult, error)
+}
+
+type checkAction[C any] struct {
+	definition CheckDefinition[C]
+}
+
+// NewCheckAction creates an action of kind check which calls the probe on every status call and fails if the expectations are violated.
+// It emits a state and a latency metric per probe which are shown by the widgets returned by CheckWidgets. Prepare and Probe are required.
+func NewCheckAction[C any](definition CheckDefinition[C]) ActionWithStatus[CheckState[C]] {
+	if definition.Prepare == nil || definition.Probe == nil {
+		log.Fatal().Msg("Prepare and Probe of the CheckDefinition are required")
+	}
+	definition.Description.Kind = action_kit_api.Check
+	definition.Description.TimeControl = action_kit_api.TimeControlInternal
+	return &checkAction[C]{definition: definition}
+}
+
+// CheckWidgets returns a state over time widget showing the outcome and a line chart showing the latency of the probes of the check action.
+func CheckWidgets(actionId, title string) []action_kit_api.Widget {
+	return []action_kit_api.Widget{
+		action_kit_api.StateOverTimeWidget{
+			Type:     action_kit_api.ComSteadybitWidgetStateOverTime,
+			Title:    title,
+			Identity: action_kit_api.StateOverTimeWidgetIdentityConfig{From: "id"},
+			Label:    action_kit_api.StateOverTimeWidgetLabelConfig{From: "label"},
+			State:    action_kit_api.StateOverTimeWidgetStateConfig{From: "state"},
+			Tooltip:  action_kit_api.StateOverTimeWidgetTooltipConfig{From: "tooltip"},
+			Value:    &action_kit_api.StateOverTimeWidgetValueConfig{Hide: new(true)},
+		},
+		action_kit_api.LineChartWidget{
+			Type:  action_kit_api.ComSteadybitWidgetLineChart,
+			Title: fmt.Sprintf("%s Latency", title),
+			Identity: action_kit_api.LineChartWidgetIdentityConfig{
+				MetricName: checkLatencyMetric(actionId),
+				From:       "id",
+				Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+			},
+			Tooltip: &action_kit_api.LineChartWidgetTooltipConfig{
+				MetricValueTitle:  new("Latency"),
+				MetricValueUnit:   new("ms"),
+				AdditionalContent: []action_kit_api.LineChartWidgetTooltipContent{{From: "tooltip", Title: "Result"}},
+			},
+		},
+	}
+}
+
+func checkStateMetric(actionId string) string {
+	return fmt.Sprintf("%s.state", actionId)
+}
+
+func checkLatencyMetric(actionId string) string {
+	return fmt.Sprintf("%s.latency", actionId)
+}
+
+func (a *checkAction[C]) NewEmptyState() CheckState[C] {
+	return CheckState[C]{}
+}
+
+func (a *checkAction[C]) Describe() action_kit_api.ActionDescription {
+	return a.definition.Description
+}
+
+func (a *checkAction[C]) Prepare(ctx context.Context, state *CheckState[C], request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	state.ExecutionId = request.ExecutionId
+	state.Label = a.definition.Description.Label
+	if request.Target != nil && request.Target.Name != "" {
+		state.Label = request.Target.Name
+	}
+	if err := a.definition.Prepare(ctx, state, request); err != nil {
+		return nil, err
+	}
+	if state.Expectations.MinSuccessRatio < 0 || state.Expectations.MinSuccessRatio > 1 {
+		return nil, extension_kit.ToError(fmt.Sprintf("The success ratio %v must be between 0 and 1.", state.Expectations.MinSuccessRatio), nil)
+	}
+	return nil, nil
+}
+
+func (a *checkAction[C]) Start(_ context.Context, state *CheckState[C]) (*action_kit_api.StartResult, error) {
+	state.StartedAt = new(time.Now())
+	return nil, nil
+}
+
+func (a *checkAction[C]) Status(ctx context.Context, state *CheckState[C]) (*action_kit_api.StatusResult, error) {
+	now := time.Now()
+	if state.StartedAt == nil {
+		state.StartedAt = new(now)
+	}
+
+	result, err := a.definition.Probe(ctx, &state.Config)
+	if err != nil {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Error: &action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Failed to run the check: %s", err.Error()),
+				Status: new(action_kit_api.Errored),
+			},
+		}, nil
+	}
+
+	state.Probes++
+	slow := state.Expectations.MaxLatency > 0 && result.Latency > state.Expectations.MaxLatency
+	if slow {
+		state.SlowProbes++
+	}
+	if result.Success {
+		state.Successes++
+		if state.FirstSuccessAt == nil {
+			state.FirstSuccessAt = new(now)
+		}
+	}
+	metrics := a.probeMetrics(state, result, slow, now)
+
+	elapsed := now.Sub(*state.StartedAt)
+	awaitingSuccess := state.Expectations.SuccessWithin > 0 && state.FirstSuccessAt == nil
+	if awaitingSuccess && elapsed >= state.Expectations.SuccessWithin || elapsed >= state.Duration {
+		return a.evaluate(state, metrics), nil
+	}
+	return &action_kit_api.StatusResult{Completed: false, Metrics: &metrics}, nil
+}
+
+func (a *checkAction[C]) probeMetrics(state *CheckState[C], result ProbeResult, slow bool, now time.Time) []action_kit_api.Metric {
+	checkState := "success"
+	if !result.Success {
+		checkState = "danger"
+	} else if slow {
+		checkState = "warn"
+	}
+	tooltip := result.Message
+	if tooltip == "" {
+		tooltip = checkState
+	}
+	fields := map[string]string{"id": state.ExecutionId.String(), "label": state.Label, "state": checkState, "tooltip": tooltip}
+
+	value := 0.0
+	if result.Success {
+		value = 1
+	}
+	return []action_kit_api.Metric{
+		{Name: new(checkStateMetric(a.definition.Description.Id)), Metric: fields, Timestamp: now, Value: value},
+		{Name: new(checkLatencyMetric(a.definition.Description.Id)), Metric: fields, Timestamp: now, Value: float64(result.Latency.Milliseconds())},
+	}
+}
+
+func (a *checkAction[C]) evaluate(state *CheckState[C], metrics []action_kit_api.Metric) *action_kit_api.StatusResult {
+	violations := checkViolations(state)
+	ratio := float64(state.Successes) / float64(max(state.Probes, 1))
+	summary := fmt.Sprintf("%d of %d probes succeeded (%.0f%%).", state.Successes, state.Probes, ratio*100)
+	result := &action_kit_api.StatusResult{
+		Completed: true,
+		Metrics:   &metrics,
+		Summary:   &action_kit_api.Summary{Level: action_kit_api.SummaryLevelInfo, Text: summary},
+	}
+	if len(violations) > 0 {
+		result.Summary.Level = action_kit_api.SummaryLevelWarning
+		result.Error = &action_kit_api.ActionKitError{
+			Title:  fmt.Sprintf("Check failed: %s", violations[0]),
+			Detail: new(strings.Join(violations, "\n")),
+			Status: new(action_kit_api.Failed),
+		}
+	}
+	return result
+}
+
+func checkViolations[C any](state *CheckState[C]) []string {
+	var violations []string
+	expectations := state.Expectations
+	if expectations.SuccessWithin > 0 {
+		if state.FirstSuccessAt == nil || state.FirstSuccessAt.Sub(*state.StartedAt) > expectations.SuccessWithin {
+			violations = append(violations, fmt.Sprintf("no successful probe within %s", expectations.SuccessWithin))
+		}
+	}
+	if expectations.MinSuccessRatio > 0 {
+		if ratio := float64(state.Successes) / float64(max(state.Probes, 1)); ratio < expectations.MinSuccessRatio {
+			violations = append(violations, fmt.Sprintf("success ratio %.0f%% is below %.0f%%", ratio*100, expectations.MinSuccessRatio*100))
+		}
+	}
+	if state.SlowProbes > 0 {
+		violations = append(violations, fmt.Sprintf("%d of %d probes exceeded the max latency of %s", state.SlowProbes, state.Probes, expectations.MaxLatency))
+	}
+	return violations
+}
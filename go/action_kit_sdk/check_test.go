@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type probeConfig struct {
+	Url string
+}
+
+func newTestCheck(expectations CheckExpectations, duration time.Duration, results ...ProbeResult) ActionWithStatus[CheckState[probeConfig]] {
+	return NewCheckAction(CheckDefinition[probeConfig]{
+		Description: action_kit_api.ActionDescription{Id: "http-check", Label: "HTTP Check", Version: "1.0.0"},
+		Prepare: func(ctx context.Context, state *CheckState[probeConfig], request action_kit_api.PrepareActionRequestBody) error {
+			state.Config.Url = "http://example.com"
+			state.Expectations = expectations
+			state.Duration = duration
+			return nil
+		},
+		Probe: func(ctx context.Context, config *probeConfig) (ProbeResult, error) {
+			if len(results) == 0 {
+				return ProbeResult{}, errors.New("no more results")
+			}
+			result := results[0]
+			results = results[1:]
+			return result, nil
+		},
+	})
+}
+
+func startCheck(t *testing.T, action ActionWithStatus[CheckState[probeConfig]]) *CheckState[probeConfig] {
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New(), Target: &action_kit_api.Target{Name: "shop"}})
+	require.NoError(t, err)
+	_, err = action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	return &state
+}
+
+func TestCheck_description(t *testing.T) {
+	action := newTestCheck(CheckExpectations{}, 0)
+	description := action.Describe()
+	assert.Equal(t, action_kit_api.Check, description.Kind)
+	assert.Equal(t, action_kit_api.TimeControlInternal, description.TimeControl)
+}
+
+func TestCheck_succeeds(t *testing.T) {
+	action := newTestCheck(CheckExpectations{MinSuccessRatio: 1}, 0, ProbeResult{Success: true, Latency: 42 * time.Millisecond, Message: "HTTP 200"})
+	state := startCheck(t, action)
+
+	result, err := action.Status(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.True(t, result.Completed)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, action_kit_api.Summary{Level: action_kit_api.SummaryLevelInfo, Text: "1 of 1 probes succeeded (100%)."}, *result.Summary)
+	require.Len(t, *result.Metrics, 2)
+	stateMetric, latencyMetric := (*result.Metrics)[0], (*result.Metrics)[1]
+	assert.Equal(t, "http-check.state", *stateMetric.Name)
+	assert.Equal(t, map[string]string{"id": state.ExecutionId.String(), "label": "shop", "state": "success", "tooltip": "HTTP 200"}, stateMetric.Metric)
+	assert.Equal(t, "http-check.latency", *latencyMetric.Name)
+	assert.Equal(t, 42.0, latencyMetric.Value)
+}
+
+func TestCheck_fails_below_success_ratio(t *testing.T) {
+	action := newTestCheck(CheckExpectations{MinSuccessRatio: 0.75}, time.Minute,
+		ProbeResult{Success: true}, ProbeResult{Success: false, Message: "HTTP 503"}, ProbeResult{Success: true})
+	state := startCheck(t, action)
+
+	var states []string
+	for range 2 {
+		result, err := action.Status(context.Background(), state)
+		require.NoError(t, err)
+		assert.False(t, result.Completed)
+		assert.Nil(t, result.Error)
+		states = append(states, (*result.Metrics)[0].Metric["state"])
+	}
+	assert.Equal(t, []string{"success", "danger"}, states)
+
+	state.StartedAt = new(time.Now().Add(-time.Minute))
+	result, err := action.Status(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.True(t, result.Completed)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, action_kit_api.Failed, *result.Error.Status)
+	assert.Equal(t, "Check failed: success ratio 67% is below 75%", result.Error.Title)
+	assert.Equal(t, action_kit_api.SummaryLevelWarning, result.Summary.Level)
+}
+
+func TestCheck_fails_if_not_successful_within(t *testing.T) {
+	action := newTestCheck(CheckExpectations{SuccessWithin: 10 * time.Second}, time.Minute, ProbeResult{Success: false}, ProbeResult{Success: false})
+	state := startCheck(t, action)
+
+	result, err := action.Status(context.Background(), state)
+	require.NoError(t, err)
+	assert.False(t, result.Completed)
+
+	state.StartedAt = new(time.Now().Add(-10 * time.Second))
+	result, err = action.Status(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.True(t, result.Completed, "completes before the end of the duration")
+	assert.Equal(t, "Check failed: no successful probe within 10s", result.Error.Title)
+}
+
+func TestCheck_fails_on_max_latency(t *testing.T) {
+	action := newTestCheck(CheckExpectations{MaxLatency: 100 * time.Millisecond}, 0, ProbeResult{Success: true, Latency: time.Second})
+	state := startCheck(t, action)
+
+	result, err := action.Status(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.Equal(t, "warn", (*result.Metrics)[0].Metric["state"])
+	assert.Equal(t, "Check failed: 1 of 1 probes exceeded the max latency of 100ms", result.Error.Title)
+}
+
+func TestCheck_errored_if_probe_fails(t *testing.T) {
+	action := newTestCheck(CheckExpectations{}, time.Minute)
+	state := startCheck(t, action)
+
+	result, err := action.Status(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.True(t, result.Completed)
+	assert.Equal(t, action_kit_api.Errored, *result.Error.Status)
+	assert.Equal(t, "Failed to run the check: no more results", result.Error.Title)
+}
+
+func TestCheck_rejects_invalid_success_ratio(t *testing.T) {
+	action := newTestCheck(CheckExpectations{MinSuccessRatio: 75}, time.Minute)
+	state := action.NewEmptyState()
+
+	_, err := action.Prepare(context.Background(), &state, action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New()})
+
+	assert.EqualError(t, err, "The success ratio 75 must be between 0 and 1.")
+}
@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+const configTag = "config"
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	bitrateType  = reflect.TypeFor[Bitrate]()
+	regexpType   = reflect.TypeFor[*regexp.Regexp]()
+	urlType      = reflect.TypeFor[*url.URL]()
+)
+
+// DecodeConfig decodes the configuration of a prepare request into the struct pointed to by out.
+// Fields are mapped to parameters using the `config:"<parameter name>"` tag, fields without tag are ignored.
+// The parameter type determines the conversion:
+//
+//   - duration: time.Duration, or an integer field receiving milliseconds
+//   - bitrate: Bitrate, or a string field receiving the value as configured (e.g. "1024kbit")
+//   - integer, percentage, stressng-workers: any integer or float field
+//   - boolean: bool
+//   - key_value: map[string]string
+//   - string_array: []string
+//   - regex: string or *regexp.Regexp
+//   - url: string or *url.URL
+//   - file: string receiving the path of the uploaded file
+//   - string, textarea: string
+//
+// Parameters without a value (null) leave the field untouched, so defaults can be set before decoding.
+// Parameters of type header, separator and target-selection carry no value and cannot be decoded.
+// All errors are collected and returned joined.
+func DecodeConfig(parameters []action_kit_api.ActionParameter, config map[string]any, out any) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config can only be decoded into a pointer to a struct, got %T", out)
+	}
+	target = target.Elem()
+
+	parametersByName := make(map[string]action_kit_api.ActionParameter, len(parameters))
+	for _, parameter := range parameters {
+		parametersByName[parameter.Name] = parameter
+	}
+
+	var errs []error
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		name, ok := field.Tag.Lookup(configTag)
+		if !ok || name == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			errs = append(errs, fmt.Errorf("field %s for parameter %q is not exported", field.Name, name))
+			continue
+		}
+		parameter, ok := parametersByName[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("field %s refers to unknown parameter %q", field.Name, name))
+			continue
+		}
+		value := config[name]
+		if value == nil {
+			continue
+		}
+		if err := decodeParameter(parameter, value, target.Field(i)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to decode parameter %q of type %s into field %s (%s): %w", name, parameter.Type, field.Name, field.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func decodeParameter(parameter action_kit_api.ActionParameter, value any, field reflect.Value) error {
+	if field.Kind() == reflect.Pointer && field.Type() != regexpType && field.Type() != urlType {
+		elem := reflect.New(field.Type().Elem())
+		if err := decodeParameter(parameter, value, elem.Elem()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch parameter.Type {
+	case action_kit_api.ActionParameterTypeDuration:
+		d, err := toDuration(value)
+		if err != nil {
+			return err
+		}
+		if field.Type() == durationType {
+			field.SetInt(int64(d))
+			return nil
+		}
+		return setNumber(field, float64(d.Milliseconds()))
+
+	case action_kit_api.ActionParameterTypeBitrate:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		b, err := ParseBitrate(s)
+		if err != nil {
+			return err
+		}
+		switch {
+		case field.Type() == bitrateType:
+			field.SetUint(uint64(b))
+		case field.Kind() == reflect.String:
+			field.SetString(s)
+		default:
+			return errUnsupportedField
+		}
+		return nil
+
+	case action_kit_api.ActionParameterTypeInteger, action_kit_api.ActionParameterTypePercentage, action_kit_api.ActionParameterTypeStressngWorkers:
+		number, ok := toNumber(value)
+		if !ok {
+			return fmt.Errorf("expected a number, got %v", value)
+		}
+		return setNumber(field, number)
+
+	case action_kit_api.ActionParameterTypeBoolean:
+		var b bool
+		switch v := value.(type) {
+		case bool:
+			b = v
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("expected a boolean, got %q", v)
+			}
+			b = parsed
+		default:
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+		if field.Kind() != reflect.Bool {
+			return errUnsupportedField
+		}
+		field.SetBool(b)
+		return nil
+
+	case action_kit_api.ActionParameterTypeKeyValue:
+		kv, err := toKeyValue(value)
+		if err != nil {
+			return err
+		}
+		if field.Type() != reflect.TypeFor[map[string]string]() {
+			return errUnsupportedField
+		}
+		field.Set(reflect.ValueOf(kv))
+		return nil
+
+	case action_kit_api.ActionParameterTypeStringArray, action_kit_api.ActionParameterTypeString1:
+		values, ok := toStringSlice(value)
+		if !ok {
+			return fmt.Errorf("expected an array of strings, got %v", value)
+		}
+		if field.Type() != reflect.TypeFor[[]string]() {
+			return errUnsupportedField
+		}
+		field.Set(reflect.ValueOf(values))
+		return nil
+
+	case action_kit_api.ActionParameterTypeRegex:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		if field.Type() == regexpType {
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(re))
+			return nil
+		}
+		return setString(field, s)
+
+	case action_kit_api.ActionParameterTypeUrl:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		if field.Type() == urlType {
+			u, err := url.Parse(s)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(u))
+			return nil
+		}
+		return setString(field, s)
+
+	case action_kit_api.ActionParameterTypeFile, action_kit_api.ActionParameterTypeString, action_kit_api.ActionParameterTypeTextarea:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		return setString(field, s)
+
+	case action_kit_api.ActionParameterTypeHeader, action_kit_api.ActionParameterTypeSeparator, action_kit_api.ActionParameterTypeTargetSelection:
+		return errors.New("parameters of this type carry no value")
+	}
+	return errors.New("unsupported parameter type")
+}
+
+var errUnsupportedField = errors.New("unsupported field type")
+
+func setString(field reflect.Value, s string) error {
+	if field.Kind() != reflect.String {
+		return errUnsupportedField
+	}
+	field.SetString(s)
+	return nil
+}
+
+func setNumber(field reflect.Value, number float64) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if number != math.Trunc(number) || field.OverflowInt(int64(number)) {
+			return fmt.Errorf("value %v does not fit", number)
+		}
+		field.SetInt(int64(number))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if number != math.Trunc(number) || number < 0 || field.OverflowUint(uint64(number)) {
+			return fmt.Errorf("value %v does not fit", number)
+		}
+		field.SetUint(uint64(number))
+	case reflect.Float32, reflect.Float64:
+		field.SetFloat(number)
+	default:
+		return errUnsupportedField
+	}
+	return nil
+}
+
+func toKeyValue(value any) (map[string]string, error) {
+	entries, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a list of key/value pairs, got %T", value)
+	}
+	result := make(map[string]string, len(entries))
+	for i, rawEntry := range entries {
+		entry, ok := rawEntry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("entry %d: expected a key/value pair, got %T", i, rawEntry)
+		}
+		key, keyOk := entry["key"].(string)
+		val, valueOk := entry["value"].(string)
+		if !keyOk || !valueOk {
+			return nil, fmt.Errorf("entry %d: expected string key and value, got %v", i, rawEntry)
+		}
+		result[key] = val
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var decoderParameters = []action_kit_api.ActionParameter{
+	{Name: "duration", Type: action_kit_api.ActionParameterTypeDuration},
+	{Name: "bandwidth", Type: action_kit_api.ActionParameterTypeBitrate},
+	{Name: "percentage", Type: action_kit_api.ActionParameterTypePercentage},
+	{Name: "workers", Type: action_kit_api.ActionParameterTypeStressngWorkers},
+	{Name: "enabled", Type: action_kit_api.ActionParameterTypeBoolean},
+	{Name: "env", Type: action_kit_api.ActionParameterTypeKeyValue},
+	{Name: "hosts", Type: action_kit_api.ActionParameterTypeStringArray},
+	{Name: "pattern", Type: action_kit_api.ActionParameterTypeRegex},
+	{Name: "script", Type: action_kit_api.ActionParameterTypeFile},
+	{Name: "settings", Type: action_kit_api.ActionParameterTypeHeader},
+	{Name: "-", Type: action_kit_api.ActionParameterTypeTargetSelection},
+}
+
+type decodedConfig struct {
+	Duration     time.Duration     `config:"duration"`
+	DurationMs   int64             `config:"duration"`
+	Bandwidth    Bitrate           `config:"bandwidth"`
+	BandwidthRaw string            `config:"bandwidth"`
+	Percentage   int               `config:"percentage"`
+	Workers      *uint             `config:"workers"`
+	Enabled      bool              `config:"enabled"`
+	Env          map[string]string `config:"env"`
+	Hosts        []string          `config:"hosts"`
+	Pattern      *regexp.Regexp    `config:"pattern"`
+	Script       string            `config:"script"`
+	Ignored      string
+}
+
+func TestDecodeConfig(t *testing.T) {
+	config := map[string]any{
+		"duration":   30000.0,
+		"bandwidth":  "1024kbit",
+		"percentage": 50.0,
+		"workers":    0.0,
+		"enabled":    true,
+		"env":        []any{map[string]any{"key": "planet", "value": "Dagobah"}},
+		"hosts":      []any{"a", "b"},
+		"pattern":    "^foo.*",
+		"script":     "/tmp/steadybit/1234/script.js",
+	}
+
+	var decoded decodedConfig
+	err := DecodeConfig(decoderParameters, config, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, 30*time.Second, decoded.Duration)
+	assert.Equal(t, int64(30000), decoded.DurationMs)
+	assert.Equal(t, Bitrate(1_024_000), decoded.Bandwidth)
+	assert.Equal(t, "1024kbit", decoded.BandwidthRaw)
+	assert.Equal(t, 50, decoded.Percentage)
+	assert.Equal(t, new(uint(0)), decoded.Workers)
+	assert.True(t, decoded.Enabled)
+	assert.Equal(t, map[string]string{"planet": "Dagobah"}, decoded.Env)
+	assert.Equal(t, []string{"a", "b"}, decoded.Hosts)
+	assert.Equal(t, "^foo.*", decoded.Pattern.String())
+	assert.Equal(t, "/tmp/steadybit/1234/script.js", decoded.Script)
+}
+
+func TestDecodeConfig_should_keep_defaults_for_missing_values(t *testing.T) {
+	decoded := decodedConfig{Percentage: 42}
+	err := DecodeConfig(decoderParameters, map[string]any{"percentage": nil}, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, 42, decoded.Percentage)
+	assert.Nil(t, decoded.Workers)
+}
+
+func TestDecodeConfig_errors(t *testing.T) {
+	var decoded struct {
+		Duration   string  `config:"duration"`
+		Bandwidth  Bitrate `config:"bandwidth"`
+		Percentage uint8   `config:"percentage"`
+		Settings   string  `config:"settings"`
+		Unknown    string  `config:"unknown"`
+	}
+	err := DecodeConfig(decoderParameters, map[string]any{
+		"duration":   1000.0,
+		"bandwidth":  "fast",
+		"percentage": 300.0,
+		"settings":   "x",
+	}, &decoded)
+
+	assert.EqualError(t, err, `failed to decode parameter "duration" of type duration into field Duration (string): unsupported field type
+failed to decode parameter "bandwidth" of type bitrate into field Bandwidth (action_kit_sdk.Bitrate): invalid bitrate "fast"
+failed to decode parameter "percentage" of type percentage into field Percentage (uint8): value 300 does not fit
+failed to decode parameter "settings" of type header into field Settings (string): parameters of this type carry no value
+field Unknown refers to unknown parameter "unknown"`)
+}
+
+func TestDecodeConfig_requires_struct_pointer(t *testing.T) {
+	assert.EqualError(t, DecodeConfig(decoderParameters, nil, decodedConfig{}), "config can only be decoded into a pointer to a struct, got action_kit_sdk.decodedConfig")
+}
+
+func TestParseBitrate(t *testing.T) {
+	tests := map[string]Bitrate{
+		"64kbit":   64_000,
+		"10mbit":   10_000_000,
+		"1024kbps": 8_192_000,
+		"10MBPS":   80_000_000,
+		"1kibit":   1024,
+		"500":      500,
+	}
+	for input, expected := range tests {
+		actual, err := ParseBitrate(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, actual, input)
+	}
+
+	_, err := ParseBitrate("10 parsecs")
+	assert.EqualError(t, err, `invalid bitrate "10 parsecs": unknown unit "parsecs"`)
+	assert.Equal(t, "8000bit", Bitrate(8000).String())
+	assert.Equal(t, uint64(1000), Bitrate(8000).BytesPerSecond())
+}
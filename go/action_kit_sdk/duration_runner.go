@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// Exiter is implemented by background processes which may exit on their own, e.g. stress, memfill or diskfill.
+type Exiter interface {
+	// Exited reports whether the process has exited and the error it exited with.
+	Exited() (bool, error)
+}
+
+// DurationState is the part of the state tracked by a DurationRunner. Add it as field to the state of the action
+// and initialize it with NewDurationState in Prepare.
+type DurationState struct {
+	ExecutionId uuid.UUID
+	// Duration of the action. Zero means the action runs until it is stopped or the process exits.
+	Duration time.Duration
+	// EndAt is set when the action is started.
+	EndAt *time.Time `json:",omitempty"`
+}
+
+// NewDurationState creates the DurationState for the execution.
+func NewDurationState(executionId uuid.UUID, duration time.Duration) DurationState {
+	return DurationState{ExecutionId: executionId, Duration: duration}
+}
+
+// DurationRunnerConfig configures a DurationRunner.
+type DurationRunnerConfig[T any] struct {
+	// Name of the process used in messages and errors, e.g. "stress".
+	Name string
+	// DurationState returns the DurationState of the action's state.
+	DurationState func(state *T) *DurationState
+	// Start starts the background process.
+	Start func(ctx context.Context, state *T) (Exiter, error)
+	// Stop stops the background process and cleans up. The process is nil if it is not known anymore, e.g. after a restart of the extension.
+	Stop func(ctx context.Context, state *T, process Exiter) error
+}
+
+// DurationRunner implements Start, Status and Stop of actions using time control internal which run a background process for a duration:
+// Status completes once the duration is over and reports early exits of the process, Stop stops the process.
+// Embed a *DurationRunner into the action or delegate the lifecycle methods to it.
+type DurationRunner[T any] struct {
+	config    DurationRunnerConfig[T]
+	processes sync.Map // map[uuid.UUID]Exiter
+}
+
+// NewDurationRunner creates a DurationRunner. DurationState, Start and Stop are required.
+func NewDurationRunner[T any](config DurationRunnerConfig[T]) *DurationRunner[T] {
+	if config.DurationState == nil || config.Start == nil || config.Stop == nil {
+		log.Fatal().Msg("DurationState, Start and Stop of the DurationRunnerConfig are required")
+	}
+	if config.Name == "" {
+		config.Name = "action"
+	}
+	return &DurationRunner[T]{config: config}
+}
+
+func (r *DurationRunner[T]) Start(ctx context.Context, state *T) (*action_kit_api.StartResult, error) {
+	durationState := r.config.DurationState(state)
+	process, err := r.config.Start(ctx, state)
+	if err != nil {
+		return nil, NewErroredError(fmt.Sprintf("Failed to start %s.", r.config.Name), err)
+	}
+	r.processes.Store(durationState.ExecutionId, process)
+
+	message := fmt.Sprintf("Started %s", r.config.Name)
+	if durationState.Duration > 0 {
+		durationState.EndAt = new(time.Now().Add(durationState.Duration))
+		message = fmt.Sprintf("Started %s for %s", r.config.Name, durationState.Duration)
+	}
+	return &action_kit_api.StartResult{
+		Messages: &[]action_kit_api.Message{{Level: new(action_kit_api.Info), Message: message}},
+	}, nil
+}
+
+func (r *DurationRunner[T]) Status(_ context.Context, state *T) (*action_kit_api.StatusResult, error) {
+	durationState := r.config.DurationState(state)
+	process, ok := r.processes.Load(durationState.ExecutionId)
+	if !ok {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Error: &action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("The %s is not running anymore.", r.config.Name),
+				Detail: new("The extension may have been restarted."),
+				Status: new(action_kit_api.Errored),
+			},
+		}, nil
+	}
+
+	if exited, err := process.(Exiter).Exited(); exited {
+		if err != nil {
+			return &action_kit_api.StatusResult{
+				Completed: true,
+				Error: &action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("The %s exited unexpectedly: %s", r.config.Name, err.Error()),
+					Status: new(action_kit_api.Failed),
+				},
+			}, nil
+		}
+		return r.completed(fmt.Sprintf("The %s exited", r.config.Name)), nil
+	}
+
+	if durationState.EndAt == nil {
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+	remaining := time.Until(*durationState.EndAt)
+	if remaining <= 0 {
+		return r.completed(fmt.Sprintf("The %s completed after %s", r.config.Name, durationState.Duration)), nil
+	}
+	return &action_kit_api.StatusResult{
+		Completed: false,
+		Messages: &[]action_kit_api.Message{{
+			Level:   new(action_kit_api.Debug),
+			Message: fmt.Sprintf("The %s is running, %s remaining", r.config.Name, remaining.Round(time.Second)),
+		}},
+	}, nil
+}
+
+func (r *DurationRunner[T]) completed(message string) *action_kit_api.StatusResult {
+	return &action_kit_api.StatusResult{
+		Completed: true,
+		Messages:  &[]action_kit_api.Message{{Level: new(action_kit_api.Info), Message: message}},
+	}
+}
+
+func (r *DurationRunner[T]) Stop(ctx context.Context, state *T) (*action_kit_api.StopResult, error) {
+	durationState := r.config.DurationState(state)
+	var process Exiter
+	if p, ok := r.processes.Load(durationState.ExecutionId); ok {
+		process = p.(Exiter)
+	}
+
+	if err := r.config.Stop(ctx, state, process); err != nil {
+		return nil, NewErroredError(fmt.Sprintf("Failed to stop %s.", r.config.Name), err)
+	}
+	r.processes.Delete(durationState.ExecutionId)
+	return &action_kit_api.StopResult{
+		Messages: &[]action_kit_api.Message{{Level: new(action_kit_api.Info), Message: fmt.Sprintf("Stopped %s", r.config.Name)}},
+	}, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProcess struct {
+	exited bool
+	err    error
+}
+
+func (p *fakeProcess) Exited() (bool, error) {
+	return p.exited, p.err
+}
+
+type runnerState struct {
+	Run DurationState
+}
+
+func newTestRunner(process *fakeProcess, stopped *[]Exiter) *DurationRunner[runnerState] {
+	return NewDurationRunner(DurationRunnerConfig[runnerState]{
+		Name:          "stress",
+		DurationState: func(state *runnerState) *DurationState { return &state.Run },
+		Start: func(ctx context.Context, state *runnerState) (Exiter, error) {
+			return process, nil
+		},
+		Stop: func(ctx context.Context, state *runnerState, process Exiter) error {
+			*stopped = append(*stopped, process)
+			return nil
+		},
+	})
+}
+
+func TestDurationRunner_completes_after_duration(t *testing.T) {
+	process := &fakeProcess{}
+	var stopped []Exiter
+	runner := newTestRunner(process, &stopped)
+	state := runnerState{Run: NewDurationState(uuid.New(), time.Minute)}
+
+	startResult, err := runner.Start(context.Background(), &state)
+	require.NoError(t, err)
+	require.NotNil(t, state.Run.EndAt)
+	assert.Equal(t, "Started stress for 1m0s", (*startResult.Messages)[0].Message)
+
+	status, err := runner.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, status.Completed)
+	assert.Equal(t, "The stress is running, 1m0s remaining", (*status.Messages)[0].Message)
+
+	state.Run.EndAt = new(time.Now().Add(-time.Second))
+	status, err = runner.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.Nil(t, status.Error)
+
+	_, err = runner.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []Exiter{process}, stopped)
+}
+
+func TestDurationRunner_reports_exit_error(t *testing.T) {
+	process := &fakeProcess{}
+	runner := newTestRunner(process, new([]Exiter))
+	state := runnerState{Run: NewDurationState(uuid.New(), time.Minute)}
+	_, err := runner.Start(context.Background(), &state)
+	require.NoError(t, err)
+
+	process.exited, process.err = true, errors.New("out of memory")
+	status, err := runner.Status(context.Background(), &state)
+
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	require.NotNil(t, status.Error)
+	assert.Equal(t, "The stress exited unexpectedly: out of memory", status.Error.Title)
+	assert.Equal(t, action_kit_api.Failed, *status.Error.Status)
+}
+
+func TestDurationRunner_unknown_process(t *testing.T) {
+	var stopped []Exiter
+	runner := newTestRunner(&fakeProcess{}, &stopped)
+	state := runnerState{Run: NewDurationState(uuid.New(), time.Minute)}
+
+	status, err := runner.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.Equal(t, action_kit_api.Errored, *status.Error.Status)
+
+	_, err = runner.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []Exiter{nil}, stopped, "stop is called without process to clean up")
+}
+
+func TestDurationRunner_start_error(t *testing.T) {
+	runner := NewDurationRunner(DurationRunnerConfig[runnerState]{
+		Name:          "stress",
+		DurationState: func(state *runnerState) *DurationState { return &state.Run },
+		Start: func(ctx context.Context, state *runnerState) (Exiter, error) {
+			return nil, errors.New("binary not found")
+		},
+		Stop: func(ctx context.Context, state *runnerState, process Exiter) error { return nil },
+	})
+
+	_, err := runner.Start(context.Background(), &runnerState{Run: NewDurationState(uuid.New(), time.Minute)})
+
+	var actionError *ActionError
+	require.ErrorAs(t, err, &actionError)
+	assert.Equal(t, "Failed to start stress.", actionError.Title)
+	assert.Equal(t, action_kit_api.Errored, actionError.Status)
+}
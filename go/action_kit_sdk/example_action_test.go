@@ -22,6 +22,8 @@ type ExampleAction struct {
 	prepareError error
 	startError   error
 	statusError  error
+	stopError    error
+	queryError   error
 }
 
 type ExampleState struct {
@@ -71,7 +73,7 @@ func (action *ExampleAction) Describe() action_kit_api.ActionDescription {
 				DefaultValue: new("10s"),
 			},
 			{
-				Name:          "duration",
+				Name:          "durationWithCustomUnits",
 				Label:         "Duration with custom units",
 				DurationUnits: new([]action_kit_api.DurationUnit{action_kit_api.DurationUnitSeconds, action_kit_api.DurationUnitMinutes}),
 				Type:          action_kit_api.ActionParameterTypeDuration,
@@ -183,6 +185,9 @@ func (action *ExampleAction) Status(_ context.Context, state *ExampleState) (*ac
 
 func (action *ExampleAction) Stop(_ context.Context, state *ExampleState) (*action_kit_api.StopResult, error) {
 	action.calls <- Call{"Stop", []any{state}}
+	if action.stopError != nil {
+		return nil, action.stopError
+	}
 	return &action_kit_api.StopResult{
 		Artifacts: &action_kit_api.Artifacts{
 			{Data: "test", Label: "artifact-stop"},
@@ -198,6 +203,9 @@ func (action *ExampleAction) Stop(_ context.Context, state *ExampleState) (*acti
 
 func (action *ExampleAction) QueryMetrics(_ context.Context, _ action_kit_api.QueryMetricsRequestBody) (*action_kit_api.QueryMetricsResult, error) {
 	action.calls <- Call{"QueryMetrics", nil}
+	if action.queryError != nil {
+		return nil, action.queryError
+	}
 	return &action_kit_api.QueryMetricsResult{
 		Artifacts: &action_kit_api.Artifacts{
 			{Data: "test", Label: "artifact-query-metrics"},
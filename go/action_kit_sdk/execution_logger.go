@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// LogFieldsKey is the key of the ActionState holding the log fields of the execution taken from the prepare request,
+// so that the logger passed to Start, Status and Stop carries them too.
+const LogFieldsKey = "_logFields"
+
+// logFields returns the fields of the prepare request added to the logger of the execution.
+func logFields(target *action_kit_api.Target, executionContext *action_kit_api.ExecutionContext) map[string]string {
+	fields := make(map[string]string)
+	if target != nil && target.Name != "" {
+		fields["targetName"] = target.Name
+	}
+	if executionContext != nil && executionContext.ExperimentKey != nil {
+		fields["experimentKey"] = *executionContext.ExperimentKey
+	}
+	return fields
+}
+
+// logFieldsFromState returns the log fields stored in the ActionState.
+func logFieldsFromState(state action_kit_api.ActionState) map[string]string {
+	fields := make(map[string]string)
+	switch stored := state[LogFieldsKey].(type) {
+	case map[string]string:
+		maps.Copy(fields, stored)
+	case map[string]any:
+		// after a round trip through JSON
+		for key, value := range stored {
+			if s, ok := value.(string); ok {
+				fields[key] = s
+			}
+		}
+	}
+	return fields
+}
+
+// withLogFields stores the log fields in the ActionState.
+func withLogFields(state action_kit_api.ActionState, fields map[string]string) {
+	if len(fields) > 0 && state != nil {
+		state[LogFieldsKey] = fields
+	}
+}
+
+// contextWithExecutionLogger returns a context carrying a logger enriched with the action id, execution id and the given fields.
+// It is based on the logger already carried by the context, e.g. added by a middleware of the extension, or the global logger.
+// Actions retrieve it with zerolog.Ctx(ctx) or log.Ctx(ctx).
+func contextWithExecutionLogger(ctx context.Context, actionId string, executionId uuid.UUID, fields map[string]string) context.Context {
+	base := log.Logger
+	if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+		base = *logger
+	}
+	logger := base.With().
+		Str("actionId", actionId).
+		Str("executionId", executionId.String())
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		logger = logger.Str(key, fields[key])
+	}
+	return logger.Logger().WithContext(ctx)
+}
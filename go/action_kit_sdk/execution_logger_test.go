@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withLogBuffer returns a request whose context carries a logger writing to the buffer.
+func withLogBuffer(r *http.Request, buf *bytes.Buffer) *http.Request {
+	return r.WithContext(zerolog.New(buf).WithContext(r.Context()))
+}
+
+// loggingInterceptor logs a line using the logger of the lifecycle call's context.
+func loggingInterceptor(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError {
+	zerolog.Ctx(ctx).Info().Msgf("%s called", call.Phase)
+	return next(ctx)
+}
+
+func TestExecutionLogger_carries_execution_fields(t *testing.T) {
+	useInmemoryStatePersister(t)
+	var buf bytes.Buffer
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)), WithoutParameterValidation(), WithInterceptor(loggingInterceptor))
+	executionId := uuid.New()
+
+	prepareBody, err := json.Marshal(action_kit_api.PrepareActionRequestBody{
+		ExecutionId:      executionId,
+		Target:           &action_kit_api.Target{Name: "shop"},
+		ExecutionContext: &action_kit_api.ExecutionContext{ExperimentKey: new("ADM-1")},
+		Config:           map[string]any{},
+	})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, withLogBuffer(httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(prepareBody)), &buf), prepareBody)
+	var prepareResult action_kit_api.PrepareResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prepareResult))
+	require.Nil(t, prepareResult.Error)
+
+	startBody, err := json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: prepareResult.State})
+	require.NoError(t, err)
+	adapter.handleStart(httptest.NewRecorder(), withLogBuffer(httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(startBody)), &buf), startBody)
+
+	expected := map[string]any{"actionId": adapter.description.Id, "executionId": executionId.String(), "targetName": "shop", "experimentKey": "ADM-1", "level": "info"}
+	var lines []string
+	for line := range bytes.Lines(buf.Bytes()) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		if message := entry["message"].(string); message == "prepare called" || message == "start called" {
+			lines = append(lines, message)
+			delete(entry, "message")
+			assert.Equal(t, expected, entry)
+		}
+	}
+	assert.Equal(t, []string{"prepare called", "start called"}, lines)
+}
+
+func TestLogFieldsFromState(t *testing.T) {
+	state := action_kit_api.ActionState{}
+	withLogFields(state, logFields(&action_kit_api.Target{Name: "shop"}, nil))
+	assert.Equal(t, map[string]string{"targetName": "shop"}, logFieldsFromState(state))
+
+	var roundTripped action_kit_api.ActionState
+	raw, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &roundTripped))
+	assert.Equal(t, map[string]string{"targetName": "shop"}, logFieldsFromState(roundTripped))
+
+	assert.Empty(t, logFieldsFromState(action_kit_api.ActionState{}))
+}
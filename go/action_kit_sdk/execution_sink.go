@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+const defaultExecutionSinkSize = 1000
+
+// ExecutionSink buffers messages and metrics of an execution which are produced in the background, e.g. by goroutines started in Start.
+// The buffer is drained into the result of the next status or stop call. When the buffer is full, the oldest entries are dropped.
+// All methods are safe for concurrent use and on a nil sink.
+type ExecutionSink struct {
+	mu              sync.Mutex
+	size            int
+	messages        []action_kit_api.Message
+	metrics         []action_kit_api.Metric
+	droppedMessages int
+	droppedMetrics  int
+}
+
+type executionSinkKey struct{}
+
+var executionSinks = sync.Map{} // map[uuid.UUID]*ExecutionSink
+
+// WithExecutionSinkSize sets the maximum number of buffered messages and metrics (each) per execution. Defaults to 1000.
+func WithExecutionSinkSize(size int) ActionOption {
+	return func(o *actionOptions) {
+		o.executionSinkSize = size
+	}
+}
+
+// ExecutionSinkFromContext returns the sink of the execution the context was passed to, or nil outside of lifecycle calls.
+func ExecutionSinkFromContext(ctx context.Context) *ExecutionSink {
+	sink, _ := ctx.Value(executionSinkKey{}).(*ExecutionSink)
+	return sink
+}
+
+// AddMessage buffers a message for the next status or stop result.
+func (s *ExecutionSink) AddMessage(message action_kit_api.Message) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.messages) >= s.size {
+		s.messages = s.messages[1:]
+		s.droppedMessages++
+	}
+	s.messages = append(s.messages, message)
+}
+
+// AddMetric buffers a metric for the next status or stop result.
+func (s *ExecutionSink) AddMetric(metric action_kit_api.Metric) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.metrics) >= s.size {
+		s.metrics = s.metrics[1:]
+		s.droppedMetrics++
+	}
+	s.metrics = append(s.metrics, metric)
+}
+
+// drain returns and clears the buffered messages and metrics. A warning is added if entries were dropped since the last drain.
+func (s *ExecutionSink) drain() ([]action_kit_api.Message, []action_kit_api.Metric) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages, metrics := s.messages, s.metrics
+	if s.droppedMessages > 0 || s.droppedMetrics > 0 {
+		messages = append(messages, action_kit_api.Message{
+			Level:   new(action_kit_api.Warn),
+			Message: fmt.Sprintf("Dropped %d message(s) and %d metric(s) because the buffer of the execution was full.", s.droppedMessages, s.droppedMetrics),
+		})
+	}
+	s.messages, s.metrics = nil, nil
+	s.droppedMessages, s.droppedMetrics = 0, 0
+	return messages, metrics
+}
+
+func getOrCreateExecutionSink(executionId uuid.UUID, size int) *ExecutionSink {
+	if size <= 0 {
+		size = defaultExecutionSinkSize
+	}
+	sink, _ := executionSinks.LoadOrStore(executionId, &ExecutionSink{size: size})
+	return sink.(*ExecutionSink)
+}
+
+func getExecutionSink(executionId uuid.UUID) *ExecutionSink {
+	sink, ok := executionSinks.Load(executionId)
+	if !ok {
+		return nil
+	}
+	return sink.(*ExecutionSink)
+}
+
+func removeExecutionSink(executionId uuid.UUID) {
+	executionSinks.Delete(executionId)
+}
+
+func clearExecutionSinks() {
+	executionSinks.Clear()
+}
+
+func contextWithExecutionSink(ctx context.Context, sink *ExecutionSink) context.Context {
+	if sink == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, executionSinkKey{}, sink)
+}
+
+// drainExecutionSink appends the buffered messages and metrics of the sink to the given result fields.
+func drainExecutionSink(sink *ExecutionSink, messages **action_kit_api.Messages, metrics **action_kit_api.Metrics) {
+	drainedMessages, drainedMetrics := sink.drain()
+	if len(drainedMessages) > 0 {
+		if *messages == nil {
+			*messages = new(action_kit_api.Messages{})
+		}
+		**messages = append(**messages, drainedMessages...)
+	}
+	if len(drainedMetrics) > 0 {
+		if *metrics == nil {
+			*metrics = new(action_kit_api.Metrics{})
+		}
+		**metrics = append(**metrics, drainedMetrics...)
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkAction struct {
+	*ExampleAction
+}
+
+func (action *sinkAction) Start(ctx context.Context, state *ExampleState) (*action_kit_api.StartResult, error) {
+	sink := ExecutionSinkFromContext(ctx)
+	sink.AddMessage(action_kit_api.Message{Message: "from background"})
+	sink.AddMetric(action_kit_api.Metric{Name: new("BackgroundMetric"), Metric: map[string]string{}})
+	return action.ExampleAction.Start(ctx, state)
+}
+
+func TestExecutionSink_drops_oldest_entries(t *testing.T) {
+	sink := &ExecutionSink{size: 2}
+	for _, m := range []string{"a", "b", "c"} {
+		sink.AddMessage(action_kit_api.Message{Message: m})
+	}
+	sink.AddMetric(action_kit_api.Metric{Metric: map[string]string{}})
+
+	messages, metrics := sink.drain()
+	require.Len(t, messages, 3)
+	assert.Equal(t, "b", messages[0].Message)
+	assert.Equal(t, "c", messages[1].Message)
+	assert.Equal(t, "Dropped 1 message(s) and 0 metric(s) because the buffer of the execution was full.", messages[2].Message)
+	assert.Equal(t, action_kit_api.Warn, *messages[2].Level)
+	assert.Len(t, metrics, 1)
+
+	messages, metrics = sink.drain()
+	assert.Empty(t, messages, "drain clears the buffer and the drop counters")
+	assert.Empty(t, metrics)
+}
+
+func TestExecutionSink_nil_is_noop(t *testing.T) {
+	sink := ExecutionSinkFromContext(context.Background())
+	assert.Nil(t, sink)
+	sink.AddMessage(action_kit_api.Message{Message: "ignored"})
+	messages, metrics := sink.drain()
+	assert.Nil(t, messages)
+	assert.Nil(t, metrics)
+}
+
+func TestExecutionSink_is_drained_into_status_and_removed_on_stop(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	adapter := newActionHttpAdapter[ExampleState](&sinkAction{NewExampleAction(calls)})
+	executionId := uuid.New()
+
+	post := func(handler func(w *httptest.ResponseRecorder, body []byte), request any, result any) {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		handler(w, body)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), result))
+	}
+
+	state := action_kit_api.ActionState{}
+	var startResult action_kit_api.StartResult
+	post(func(w *httptest.ResponseRecorder, body []byte) {
+		adapter.handleStart(w, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	}, action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: state}, &startResult)
+	defer stopMonitorHeartbeat(executionId)
+	require.Nil(t, startResult.Error)
+	assert.Len(t, *startResult.Messages, 1, "the sink is not drained into the start result")
+
+	var statusResult action_kit_api.StatusResult
+	post(func(w *httptest.ResponseRecorder, body []byte) {
+		adapter.handleStatus(w, httptest.NewRequest("POST", adapter.description.Status.Path, bytes.NewReader(body)), body)
+	}, action_kit_api.ActionStatusRequestBody{ExecutionId: executionId, State: *startResult.State}, &statusResult)
+	require.Len(t, *statusResult.Messages, 2)
+	assert.Equal(t, "from background", (*statusResult.Messages)[1].Message)
+	require.Len(t, *statusResult.Metrics, 2)
+	assert.Equal(t, "BackgroundMetric", *(*statusResult.Metrics)[1].Name)
+
+	var stopResult action_kit_api.StopResult
+	post(func(w *httptest.ResponseRecorder, body []byte) {
+		adapter.handleStop(w, httptest.NewRequest("POST", adapter.description.Stop.Path, bytes.NewReader(body)), body)
+	}, action_kit_api.StopActionRequestBody{ExecutionId: executionId, State: *statusResult.State}, &stopResult)
+	assert.Len(t, *stopResult.Messages, 1)
+	assert.Nil(t, getExecutionSink(executionId))
+}
+
+func TestExecutionSink_is_removed_if_stop_by_extension_fails(t *testing.T) {
+	useInmemoryStatePersister(t)
+	action := NewExampleAction(make(chan Call, 10))
+	action.stopError = errors.New("stop failed")
+	actionId := action.Describe().Id
+	registeredActions[actionId] = action
+	t.Cleanup(func() { delete(registeredActions, actionId) })
+
+	executionId := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: actionId, State: action_kit_api.ActionState{}}))
+	getOrCreateExecutionSink(executionId, 0)
+
+	assert.Error(t, stopAction(context.Background(), executionId, "heartbeat timeout"))
+	assert.Nil(t, getExecutionSink(executionId))
+}
+
+func TestExecutionSink_is_removed_by_stop_all(t *testing.T) {
+	useInmemoryStatePersister(t)
+	executionId := uuid.New()
+	getOrCreateExecutionSink(executionId, 0)
+
+	StopAllActiveActions("test")
+	assert.Nil(t, getExecutionSink(executionId), "executions of actions without stop are not persisted, their sink is removed anyway")
+}
+
+func TestExecutionSink_is_monitored_for_actions_without_stop(t *testing.T) {
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(nil))
+	adapter.description.Stop = nil
+
+	executionId := uuid.New()
+	adapter.startHeartbeatMonitor(executionId)
+	defer stopMonitorHeartbeat(executionId)
+	_, monitored := heartbeatMonitors.Load(executionId)
+	assert.True(t, monitored, "the sink is released if the agent is gone before the action completed")
+}
@@ -0,0 +1,285 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+const (
+	defaultUploadDirectory = "/tmp/steadybit"
+	defaultMaxUploadSize   = 10 << 20
+	maxRequestPartSize     = 10 << 20
+)
+
+var uploadDirectory = defaultUploadDirectory
+
+// UploadedFile describes a file uploaded for a parameter of type 'file'.
+type UploadedFile struct {
+	// Path of the file on disk, also passed as value of the parameter in the prepare config.
+	Path string
+	// Size in bytes.
+	Size int64
+	// ContentType as sent by the agent.
+	ContentType string
+	// SHA256 is the hex encoded SHA-256 checksum of the content.
+	SHA256 string
+}
+
+type uploadedFilesKey struct{}
+
+// uploadViolation is returned if the uploaded files don't match the size limit or accepted file types of the action.
+type uploadViolation struct {
+	title  string
+	detail string
+}
+
+func (v *uploadViolation) Error() string {
+	return v.title
+}
+
+func (v *uploadViolation) toActionKitError() *action_kit_api.ActionKitError {
+	err := &action_kit_api.ActionKitError{Title: v.title}
+	if v.detail != "" {
+		err.Detail = new(v.detail)
+	}
+	return err
+}
+
+// SetUploadDirectory sets the directory in which files uploaded for parameters of type 'file' are stored, one folder per execution.
+// Defaults to /tmp/steadybit. Must be called before any action is registered.
+func SetUploadDirectory(directory string) {
+	if directory == "" {
+		log.Fatal().Msg("upload directory must not be empty")
+	}
+	uploadDirectory = directory
+}
+
+// WithMaxUploadSize limits the total size in bytes of the files uploaded in a single prepare request. Defaults to 10 MiB.
+func WithMaxUploadSize(bytes int64) ActionOption {
+	return func(o *actionOptions) {
+		o.maxUploadSize = bytes
+	}
+}
+
+// UploadedFilesFromContext returns the files uploaded for the prepare call, keyed by parameter name.
+func UploadedFilesFromContext(ctx context.Context) map[string]UploadedFile {
+	files, _ := ctx.Value(uploadedFilesKey{}).(map[string]UploadedFile)
+	return files
+}
+
+func contextWithUploadedFiles(ctx context.Context, files map[string]UploadedFile) context.Context {
+	if len(files) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, uploadedFilesKey{}, files)
+}
+
+func uploadFolder(executionId uuid.UUID) string {
+	return filepath.Join(uploadDirectory, executionId.String())
+}
+
+func removeUploadFolder(executionId uuid.UUID) {
+	folder := uploadFolder(executionId)
+	if _, err := os.Stat(folder); os.IsNotExist(err) {
+		return
+	}
+	if err := os.RemoveAll(folder); err != nil {
+		log.Error().Err(err).Msgf("Could not remove directory '%s'", folder)
+	} else {
+		log.Debug().Msgf("Directory '%s' removed successfully", folder)
+	}
+}
+
+// errAnsweredByPreviousCall is returned by receiveUploads if the request was answered with the result of a previous call for the execution.
+var errAnsweredByPreviousCall = errors.New("answered by previous call")
+
+// receiveUploads streams the files of a multipart prepare request to the upload folder of the execution.
+// Files are written to a staging folder first, as the request part might be sent after the files.
+func (a *actionHttpAdapter[T]) receiveUploads(r *http.Request) (*action_kit_api.PrepareActionRequestBody, map[string]UploadedFile, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(uploadDirectory, 0755); err != nil {
+		return nil, nil, err
+	}
+	staging, err := os.MkdirTemp(uploadDirectory, ".upload-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(staging)
+	}()
+
+	remaining := a.options.maxUploadSize
+	if remaining <= 0 {
+		remaining = defaultMaxUploadSize
+	}
+
+	var request *action_kit_api.PrepareActionRequestBody
+	files := make(map[string]UploadedFile)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if part.FileName() == "" {
+			if part.FormName() == "request" {
+				content, err := io.ReadAll(io.LimitReader(part, maxRequestPartSize))
+				if err != nil {
+					return nil, nil, err
+				}
+				request = &action_kit_api.PrepareActionRequestBody{}
+				if err := json.Unmarshal(content, request); err != nil {
+					return nil, nil, fmt.Errorf("failed to parse request body: %w", err)
+				}
+			}
+			continue
+		}
+
+		parameterName := part.FormName()
+		if parameterName == "" || parameterName != filepath.Base(parameterName) || parameterName == ".." {
+			return nil, nil, &uploadViolation{title: fmt.Sprintf("Invalid parameter name %q for uploaded file.", parameterName)}
+		}
+		if _, exists := files[parameterName]; exists {
+			return nil, nil, &uploadViolation{title: fmt.Sprintf("Too many files for parameter %s.", parameterName)}
+		}
+		filename := filepath.Base(part.FileName())
+		contentType := part.Header.Get("Content-Type")
+		if !a.isAcceptedFileType(parameterName, filename, contentType) {
+			return nil, nil, &uploadViolation{
+				title:  fmt.Sprintf("The file %s is not accepted for parameter %s.", filename, parameterName),
+				detail: fmt.Sprintf("Accepted file types: %s", strings.Join(a.acceptedFileTypes(parameterName), ", ")),
+			}
+		}
+
+		log.Debug().Msgf("Save File: Parameter %s, File %s", parameterName, filename)
+		// files are kept per parameter, so that files with the same name for different parameters don't overwrite each other
+		path := filepath.Join(parameterName, filename)
+		if err := os.Mkdir(filepath.Join(staging, parameterName), 0755); err != nil {
+			return nil, nil, err
+		}
+		size, checksum, err := saveFile(filepath.Join(staging, path), part, remaining)
+		if err != nil {
+			return nil, nil, err
+		}
+		remaining -= size
+		files[parameterName] = UploadedFile{Path: path, Size: size, ContentType: contentType, SHA256: checksum}
+	}
+
+	if request == nil {
+		return nil, nil, errors.New("multipart request body is missing the 'request' part")
+	}
+	// claimed before the files of a previous call for the execution are replaced
+	if !claimIdempotentCall(r, request.ExecutionId) {
+		return nil, nil, errAnsweredByPreviousCall
+	}
+	if len(files) == 0 {
+		return request, nil, nil
+	}
+
+	folder := uploadFolder(request.ExecutionId)
+	if err := os.RemoveAll(folder); err != nil {
+		return nil, nil, err
+	}
+	if err := os.Rename(staging, folder); err != nil {
+		return nil, nil, err
+	}
+	if request.Config == nil {
+		request.Config = make(map[string]any)
+	}
+	for parameterName, file := range files {
+		file.Path = filepath.Join(folder, file.Path)
+		files[parameterName] = file
+		request.Config[parameterName] = file.Path
+	}
+	return request, files, nil
+}
+
+// saveFile streams the content to the file and returns its size and SHA-256 checksum. Fails if more than limit bytes are read.
+func saveFile(filename string, content io.Reader, limit int64) (int64, string, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to close file %s", file.Name())
+		}
+	}(file)
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(content, limit+1))
+	if err != nil {
+		return 0, "", err
+	}
+	if size > limit {
+		return 0, "", &uploadViolation{title: fmt.Sprintf("The uploaded files exceed the maximum size of %d bytes.", limit)}
+	}
+	if err = file.Sync(); err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (a *actionHttpAdapter[T]) acceptedFileTypes(parameterName string) []string {
+	for _, parameter := range a.description.Parameters {
+		if parameter.Name == parameterName && parameter.AcceptedFileTypes != nil {
+			return *parameter.AcceptedFileTypes
+		}
+	}
+	return nil
+}
+
+// isAcceptedFileType matches the file against the unique file type specifiers of the parameter: extensions (".txt"), MIME types ("text/plain") and wildcards ("text/*").
+func (a *actionHttpAdapter[T]) isAcceptedFileType(parameterName, filename, contentType string) bool {
+	accepted := a.acceptedFileTypes(parameterName)
+	if len(accepted) == 0 {
+		return true
+	}
+
+	extension := strings.ToLower(filepath.Ext(filename))
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(mime.TypeByExtension(extension))
+	}
+
+	for _, specifier := range accepted {
+		specifier = strings.ToLower(strings.TrimSpace(specifier))
+		switch {
+		case strings.HasPrefix(specifier, "."):
+			if specifier == extension {
+				return true
+			}
+		case strings.HasSuffix(specifier, "/*"):
+			if mediaType != "" && strings.HasPrefix(mediaType, strings.TrimSuffix(specifier, "*")) {
+				return true
+			}
+		case specifier == mediaType:
+			return true
+		}
+	}
+	return false
+}
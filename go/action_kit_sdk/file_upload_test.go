@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadAction struct {
+	*ExampleAction
+	files map[string]UploadedFile
+}
+
+func (action *uploadAction) Prepare(ctx context.Context, state *ExampleState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	action.files = UploadedFilesFromContext(ctx)
+	return action.ExampleAction.Prepare(ctx, state, request)
+}
+
+func useUploadDirectory(t *testing.T) string {
+	previous := uploadDirectory
+	t.Cleanup(func() { uploadDirectory = previous })
+	uploadDirectory = t.TempDir()
+	return uploadDirectory
+}
+
+func prepareWithFile(t *testing.T, adapter *actionHttpAdapter[ExampleState], executionId uuid.UUID, filename string, content []byte) action_kit_api.PrepareResult {
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, newPrepareRequestWithFile(t, adapter, executionId, filename, content), nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	var result action_kit_api.PrepareResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+func newPrepareRequestWithFile(t *testing.T, adapter *actionHttpAdapter[ExampleState], executionId uuid.UUID, filename string, content []byte) *http.Request {
+	requestBody, err := json.Marshal(action_kit_api.PrepareActionRequestBody{
+		ExecutionId: executionId,
+		Config:      map[string]any{"duration": "10s"},
+	})
+	require.NoError(t, err)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	// the file is sent before the request part on purpose
+	partFile, err := writer.CreateFormFile("inputFile", filename)
+	require.NoError(t, err)
+	_, _ = partFile.Write(content)
+	partRequest, err := writer.CreateFormField("request")
+	require.NoError(t, err)
+	_, _ = partRequest.Write(requestBody)
+	require.NoError(t, writer.Close())
+
+	r := httptest.NewRequest("POST", adapter.description.Prepare.Path, &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r
+}
+
+func TestHandlePrepare_streams_upload_with_checksum(t *testing.T) {
+	useInmemoryStatePersister(t)
+	directory := useUploadDirectory(t)
+	action := &uploadAction{ExampleAction: NewExampleAction(make(chan Call, 10))}
+	adapter := newActionHttpAdapter[ExampleState](action)
+	executionId := uuid.New()
+
+	result := prepareWithFile(t, adapter, executionId, "script.txt", []byte("This is a test file"))
+	require.Nil(t, result.Error)
+
+	file, ok := action.files["inputFile"]
+	require.True(t, ok)
+	checksum := sha256.Sum256([]byte("This is a test file"))
+	assert.Equal(t, hex.EncodeToString(checksum[:]), file.SHA256)
+	assert.Equal(t, int64(19), file.Size)
+	assert.Equal(t, uploadFolder(executionId)+"/inputFile/script.txt", file.Path)
+	assert.Equal(t, file.Path, result.State["InputFile"])
+
+	content, err := os.ReadFile(file.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "This is a test file", string(content))
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the staging folder is removed")
+}
+
+func TestHandlePrepare_keeps_files_with_same_name_per_parameter(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useUploadDirectory(t)
+	action := &uploadAction{ExampleAction: NewExampleAction(make(chan Call, 10))}
+	adapter := newActionHttpAdapter[ExampleState](action)
+	adapter.description.Parameters = append(adapter.description.Parameters, action_kit_api.ActionParameter{Name: "otherFile", Type: action_kit_api.ActionParameterTypeFile})
+
+	requestBody, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New(), Config: map[string]any{"duration": "10s"}})
+	require.NoError(t, err)
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, parameterName := range []string{"inputFile", "otherFile"} {
+		partFile, err := writer.CreateFormFile(parameterName, "script.txt")
+		require.NoError(t, err)
+		_, _ = partFile.Write([]byte(parameterName))
+	}
+	partRequest, err := writer.CreateFormField("request")
+	require.NoError(t, err)
+	_, _ = partRequest.Write(requestBody)
+	require.NoError(t, writer.Close())
+
+	r := httptest.NewRequest("POST", adapter.description.Prepare.Path, &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, r, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	for _, parameterName := range []string{"inputFile", "otherFile"} {
+		content, err := os.ReadFile(action.files[parameterName].Path)
+		require.NoError(t, err)
+		assert.Equal(t, parameterName, string(content))
+	}
+}
+
+func TestHandlePrepare_rejects_too_large_upload(t *testing.T) {
+	useInmemoryStatePersister(t)
+	directory := useUploadDirectory(t)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)), WithMaxUploadSize(10))
+
+	result := prepareWithFile(t, adapter, uuid.New(), "script.txt", []byte("This is a test file"))
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "The uploaded files exceed the maximum size of 10 bytes.", result.Error.Title)
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestHandlePrepare_rejects_file_type(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useUploadDirectory(t)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)))
+
+	result := prepareWithFile(t, adapter, uuid.New(), "capture.pcap", []byte{0xd4, 0xc3, 0xb2, 0xa1})
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "The file capture.pcap is not accepted for parameter inputFile.", result.Error.Title)
+	assert.Equal(t, "Accepted file types: .txt", *result.Error.Detail)
+}
+
+func TestHandlePrepare_removes_upload_folder_if_prepare_fails(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useUploadDirectory(t)
+	action := NewExampleAction(make(chan Call, 10))
+	action.prepareError = errors.New("prepare failed")
+	adapter := newActionHttpAdapter[ExampleState](action)
+	executionId := uuid.New()
+
+	result := prepareWithFile(t, adapter, executionId, "script.txt", []byte("This is a test file"))
+	require.NotNil(t, result.Error)
+
+	_, err := os.Stat(uploadFolder(executionId))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestIsAcceptedFileType(t *testing.T) {
+	adapter := &actionHttpAdapter[ExampleState]{description: action_kit_api.ActionDescription{Parameters: []action_kit_api.ActionParameter{
+		{Name: "script", Type: action_kit_api.ActionParameterTypeFile, AcceptedFileTypes: new([]string{".jmx", "text/csv", "image/*"})},
+		{Name: "any", Type: action_kit_api.ActionParameterTypeFile},
+	}}}
+
+	assert.True(t, adapter.isAcceptedFileType("script", "test.JMX", "application/octet-stream"))
+	assert.True(t, adapter.isAcceptedFileType("script", "data", "text/csv; charset=utf-8"))
+	assert.True(t, adapter.isAcceptedFileType("script", "logo.png", "application/octet-stream"), "the MIME type is derived from the extension")
+	assert.False(t, adapter.isAcceptedFileType("script", "test.js", "text/javascript"))
+	assert.True(t, adapter.isAcceptedFileType("any", "test.js", ""))
+}
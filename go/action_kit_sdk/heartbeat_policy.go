@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+const defaultHeartbeatTimeoutMultiplier = 4
+
+// HeartbeatPolicy controls how the SDK detects a lost agent. The agent sends a heartbeat with each status call,
+// if none arrives within the timeout, the execution is stopped by the extension.
+type HeartbeatPolicy struct {
+	// Disabled turns off the heartbeat monitoring. The same is achieved by the action_kit_api.DISABLEHEARTBEAT additional flag.
+	Disabled bool
+	// TimeoutMultiplier is multiplied with the status call interval to compute the timeout. Defaults to 4.
+	TimeoutMultiplier int
+	// MinGracePeriod is the minimum time without heartbeat before the execution is stopped.
+	MinGracePeriod time.Duration
+	// OnTimeout is called when the heartbeat timed out, before the execution is stopped.
+	OnTimeout func(executionId uuid.UUID)
+}
+
+// WithHeartbeatPolicy sets the heartbeat policy of the action.
+func WithHeartbeatPolicy(policy HeartbeatPolicy) ActionOption {
+	return func(o *actionOptions) {
+		o.heartbeatPolicy = policy
+	}
+}
+
+// heartbeatTimings computes the interval of the heartbeat checks and the timeout for the status call interval of the action.
+func (p HeartbeatPolicy) heartbeatTimings(callInterval time.Duration) (interval, timeout time.Duration) {
+	interval = max(callInterval, minHeartbeatInterval)
+	multiplier := p.TimeoutMultiplier
+	if multiplier <= 0 {
+		multiplier = defaultHeartbeatTimeoutMultiplier
+	}
+	timeout = max(interval*time.Duration(multiplier), p.MinGracePeriod)
+	return interval, timeout
+}
+
+func isHeartbeatDisabled(description action_kit_api.ActionDescription, policy HeartbeatPolicy) bool {
+	if policy.Disabled {
+		return true
+	}
+	return description.AdditionalFlags != nil && slices.Contains(*description.AdditionalFlags, action_kit_api.DISABLEHEARTBEAT)
+}
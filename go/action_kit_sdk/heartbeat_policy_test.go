@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatPolicy_timings(t *testing.T) {
+	interval, timeout := HeartbeatPolicy{}.heartbeatTimings(time.Second)
+	assert.Equal(t, 5*time.Second, interval, "interval is raised to the minimum")
+	assert.Equal(t, 20*time.Second, timeout)
+
+	interval, timeout = HeartbeatPolicy{TimeoutMultiplier: 10}.heartbeatTimings(10 * time.Second)
+	assert.Equal(t, 10*time.Second, interval)
+	assert.Equal(t, 100*time.Second, timeout)
+
+	_, timeout = HeartbeatPolicy{MinGracePeriod: 5 * time.Minute}.heartbeatTimings(10 * time.Second)
+	assert.Equal(t, 5*time.Minute, timeout)
+}
+
+func TestStartHeartbeatMonitor_respects_disable_heartbeat_flag(t *testing.T) {
+	action := NewExampleAction(nil)
+	adapter := newActionHttpAdapter[ExampleState](action)
+	adapter.description.AdditionalFlags = new([]action_kit_api.ActionDescriptionAdditionalFlags{action_kit_api.DISABLEHEARTBEAT})
+
+	executionId := uuid.New()
+	adapter.startHeartbeatMonitor(executionId)
+	_, monitored := heartbeatMonitors.Load(executionId)
+	assert.False(t, monitored)
+
+	adapter.description.AdditionalFlags = nil
+	adapter.startHeartbeatMonitor(executionId)
+	_, monitored = heartbeatMonitors.Load(executionId)
+	assert.True(t, monitored)
+	stopMonitorHeartbeat(executionId)
+}
+
+func TestStartHeartbeatMonitor_respects_disabled_policy(t *testing.T) {
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(nil), WithHeartbeatPolicy(HeartbeatPolicy{Disabled: true}))
+
+	executionId := uuid.New()
+	adapter.startHeartbeatMonitor(executionId)
+	_, monitored := heartbeatMonitors.Load(executionId)
+	assert.False(t, monitored)
+}
+
+func TestMonitorHeartbeat_calls_on_timeout(t *testing.T) {
+	executionId := uuid.New()
+	timedOut := make(chan uuid.UUID, 1)
+	monitorHeartbeat(executionId, 10*time.Millisecond, 20*time.Millisecond, func(id uuid.UUID) {
+		timedOut <- id
+	})
+	defer stopMonitorHeartbeat(executionId)
+
+	select {
+	case id := <-timedOut:
+		assert.Equal(t, executionId, id)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "OnTimeout was not called")
+	}
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-kit/exthttp"
+)
+
+// DefaultResultCacheSize is the number of lifecycle results kept to answer repeated calls.
+const DefaultResultCacheSize = 1000
+
+var lifecycleResults = newResultCache(DefaultResultCacheSize)
+
+// SetResultCacheSize sets the number of prepare, start and stop results kept to answer repeated calls for the same execution id,
+// e.g. retries of the agent after a network issue. The oldest results are evicted first, 0 disables the cache.
+// Must be called before any action is registered.
+func SetResultCacheSize(size int) {
+	lifecycleResults = newResultCache(size)
+}
+
+type resultCacheKey struct {
+	phase       LifecyclePhase
+	executionId uuid.UUID
+}
+
+// cachedResult is the response of a lifecycle call. done is closed once the call returned.
+// Only the content type is kept from the headers, others like Content-Encoding are set by middlewares for each response.
+type cachedResult struct {
+	done        chan struct{}
+	cacheable   bool
+	status      int
+	contentType string
+	body        []byte
+}
+
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[resultCacheKey]*cachedResult
+	order   []resultCacheKey
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{size: size, entries: make(map[resultCacheKey]*cachedResult)}
+}
+
+// begin returns the entry of a previous call for the key, or creates a new one. owner is true if the caller has to perform the call.
+func (c *resultCache) begin(key resultCacheKey) (entry *cachedResult, owner bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		return entry, false
+	}
+	entry = &cachedResult{done: make(chan struct{})}
+	if c.size <= 0 {
+		return entry, true
+	}
+	c.entries[key] = entry
+	c.order = append(c.order, key)
+	for len(c.order) > c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	return entry, true
+}
+
+// forget removes the entry, so that the next call for the key is performed again.
+func (c *resultCache) forget(key resultCacheKey, entry *cachedResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+}
+
+// recordingResponseWriter passes the response through and keeps a copy of it. written is false if the handler returned without a response.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	body    []byte
+	written bool
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.body = append(w.body, b...)
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// idempotent answers repeated calls for the same execution id with the response of the first successful call instead of calling the handler again.
+// A repeated call arriving while the first one is still running waits for it.
+//
+// Responses with a 2xx status are cached, also if the result reports an error, e.g. a failed prepare: the action was called and a retry gets
+// the same outcome. Stop results reporting an error are not cached, the agent retries failed reverts. Other responses, e.g. for invalid
+// requests or errors returned by the action, and calls for which the handler wrote no response are not cached and performed again.
+//
+// The execution id of multipart prepare requests is known only once the request was received, the handler claims these calls using
+// claimIdempotentCall.
+func (a *actionHttpAdapter[T]) idempotent(phase LifecyclePhase, handler exthttp.Handler) exthttp.Handler {
+	return func(w http.ResponseWriter, r *http.Request, body []byte) {
+		call := &idempotentCall{
+			actionId: a.description.Id,
+			phase:    phase,
+			w:        w,
+			recorder: &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK},
+		}
+		defer call.finish()
+
+		if isMultipartRequest(r) {
+			handler(call.recorder, r.WithContext(context.WithValue(r.Context(), idempotentCallKey{}, call)), body)
+			return
+		}
+
+		var request struct {
+			ExecutionId uuid.UUID `json:"executionId"`
+		}
+		if body == nil || json.Unmarshal(body, &request) != nil || request.ExecutionId == uuid.Nil {
+			// the handler reports invalid bodies
+			handler(w, r, body)
+			return
+		}
+		if call.claim(r.Context(), request.ExecutionId) {
+			handler(call.recorder, r, body)
+		}
+	}
+}
+
+type idempotentCallKey struct{}
+
+// claimIdempotentCall claims the lifecycle call of the execution for requests whose execution id is known only after reading the body.
+// Returns false if the call must not be performed, the response of a previous call was written then.
+func claimIdempotentCall(r *http.Request, executionId uuid.UUID) bool {
+	call, ok := r.Context().Value(idempotentCallKey{}).(*idempotentCall)
+	if !ok {
+		return true
+	}
+	return call.claim(r.Context(), executionId)
+}
+
+// idempotentCall is a lifecycle call answered with the result of a previous call for the same execution id or recording its result.
+type idempotentCall struct {
+	actionId string
+	phase    LifecyclePhase
+	w        http.ResponseWriter
+	recorder *recordingResponseWriter
+	key      resultCacheKey
+	// entry is set once the call was claimed.
+	entry *cachedResult
+}
+
+// claim returns true if the call has to be performed. Otherwise, the response of the previous call was written or the request was canceled.
+func (c *idempotentCall) claim(ctx context.Context, executionId uuid.UUID) bool {
+	c.key = resultCacheKey{phase: c.phase, executionId: executionId}
+	for {
+		entry, owner := lifecycleResults.begin(c.key)
+		if owner {
+			c.entry = entry
+			return true
+		}
+
+		log.Info().
+			Str("actionId", c.actionId).
+			Str("executionId", executionId.String()).
+			Msgf("Received repeated %s call, waiting for the result of the previous call.", c.phase)
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return false
+		}
+		if entry.cacheable {
+			log.Info().
+				Str("actionId", c.actionId).
+				Str("executionId", executionId.String()).
+				Msgf("Answering repeated %s call with the result of the previous call.", c.phase)
+			if entry.contentType != "" {
+				c.w.Header().Set("Content-Type", entry.contentType)
+			}
+			c.w.WriteHeader(entry.status)
+			_, _ = c.w.Write(entry.body)
+			return false
+		}
+		// the previous call failed, perform the call again
+	}
+}
+
+// finish records the response of a claimed call.
+func (c *idempotentCall) finish() {
+	entry := c.entry
+	if entry == nil {
+		return
+	}
+	entry.cacheable = c.recorder.written && c.recorder.status < http.StatusMultipleChoices && !(c.phase == PhaseStop && reportsError(c.recorder.body))
+	entry.status = c.recorder.status
+	entry.contentType = c.w.Header().Get("Content-Type")
+	entry.body = c.recorder.body
+	if !entry.cacheable {
+		lifecycleResults.forget(c.key, entry)
+	}
+	close(entry.done)
+}
+
+// reportsError returns true if the result in the body carries an error.
+func reportsError(body []byte) bool {
+	var result struct {
+		Error json.RawMessage `json:"error"`
+	}
+	return json.Unmarshal(body, &result) == nil && len(result.Error) > 0 && string(result.Error) != "null"
+}
+
+func isMultipartRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data")
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/exthttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useResultCacheSize(t *testing.T, size int) {
+	previous := lifecycleResults
+	SetResultCacheSize(size)
+	t.Cleanup(func() { lifecycleResults = previous })
+}
+
+func TestIdempotent_returns_cached_result_of_repeated_start(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useResultCacheSize(t, 10)
+	calls := make(chan Call, 10)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(calls))
+	handler := adapter.idempotent(PhaseStart, adapter.handleStart)
+
+	body, err := json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: uuid.New(), State: action_kit_api.ActionState{}})
+	require.NoError(t, err)
+	first := httptest.NewRecorder()
+	handler(first, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	repeated := httptest.NewRecorder()
+	handler(repeated, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+
+	assert.Equal(t, 200, repeated.Code)
+	assert.Equal(t, first.Body.String(), repeated.Body.String())
+	assert.Equal(t, first.Header().Get("Content-Type"), repeated.Header().Get("Content-Type"))
+	assert.Len(t, calls, 1, "start is called once")
+}
+
+func TestIdempotent_calls_again_after_failure(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useResultCacheSize(t, 10)
+	calls := make(chan Call, 10)
+	action := NewExampleAction(calls)
+	action.stopError = errors.New("boom")
+	adapter := newActionHttpAdapter[ExampleState](action, WithStopPolicy(StopPolicy{MaxAttempts: 1}))
+	handler := adapter.idempotent(PhaseStop, adapter.handleStop)
+
+	body, err := json.Marshal(action_kit_api.StopActionRequestBody{ExecutionId: uuid.New(), State: action_kit_api.ActionState{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", adapter.description.Stop.Path, bytes.NewReader(body)), body)
+	require.Equal(t, 500, w.Code)
+
+	action.stopError = nil
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", adapter.description.Stop.Path, bytes.NewReader(body)), body)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Len(t, calls, 2, "failed stop is not cached")
+}
+
+func TestIdempotent_replays_only_content_type(t *testing.T) {
+	useResultCacheSize(t, 10)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)))
+	handler := adapter.idempotent(PhaseStart, func(w http.ResponseWriter, _ *http.Request, _ []byte) {
+		// as set by a compressing middleware
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Vary", "Accept-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	body, err := json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: uuid.New()})
+	require.NoError(t, err)
+	handler(httptest.NewRecorder(), httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	repeated := httptest.NewRecorder()
+	handler(repeated, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, repeated.Header())
+	assert.Equal(t, `{}`, repeated.Body.String())
+}
+
+func TestIdempotent_caches_results_with_error(t *testing.T) {
+	useResultCacheSize(t, 10)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)))
+	var calls int
+	handler := adapter.idempotent(PhasePrepare, func(w http.ResponseWriter, _ *http.Request, _ []byte) {
+		calls++
+		exthttp.WriteBody(w, action_kit_api.PrepareResult{Error: &action_kit_api.ActionKitError{Title: "failed"}})
+	})
+
+	body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New()})
+	require.NoError(t, err)
+	first := httptest.NewRecorder()
+	handler(first, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+	repeated := httptest.NewRecorder()
+	handler(repeated, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+
+	assert.Equal(t, first.Body.String(), repeated.Body.String())
+	assert.Equal(t, 1, calls, "the outcome of the action is replayed")
+}
+
+func TestIdempotent_returns_cached_result_of_repeated_multipart_prepare(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useUploadDirectory(t)
+	useResultCacheSize(t, 10)
+	calls := make(chan Call, 10)
+	action := &uploadAction{ExampleAction: NewExampleAction(calls)}
+	adapter := newActionHttpAdapter[ExampleState](action)
+	handler := adapter.idempotent(PhasePrepare, adapter.handlePrepare)
+	executionId := uuid.New()
+
+	first := httptest.NewRecorder()
+	handler(first, newPrepareRequestWithFile(t, adapter, executionId, "script.txt", []byte("first")), nil)
+	require.Equal(t, 200, first.Code, first.Body.String())
+	repeated := httptest.NewRecorder()
+	handler(repeated, newPrepareRequestWithFile(t, adapter, executionId, "script.txt", []byte("second")), nil)
+
+	assert.Equal(t, first.Body.String(), repeated.Body.String())
+	assert.Len(t, calls, 1, "prepare is called once")
+	content, err := os.ReadFile(action.files["inputFile"].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content), "the files of the first call are kept")
+}
+
+func TestIdempotent_distinguishes_phases_and_executions(t *testing.T) {
+	useResultCacheSize(t, 10)
+	executionId := uuid.New()
+
+	_, owner := lifecycleResults.begin(resultCacheKey{phase: PhaseStart, executionId: executionId})
+	assert.True(t, owner)
+	_, owner = lifecycleResults.begin(resultCacheKey{phase: PhaseStop, executionId: executionId})
+	assert.True(t, owner)
+	_, owner = lifecycleResults.begin(resultCacheKey{phase: PhaseStart, executionId: uuid.New()})
+	assert.True(t, owner)
+	_, owner = lifecycleResults.begin(resultCacheKey{phase: PhaseStart, executionId: executionId})
+	assert.False(t, owner)
+}
+
+func TestResultCache_evicts_oldest(t *testing.T) {
+	cache := newResultCache(2)
+	keys := []resultCacheKey{{PhaseStart, uuid.New()}, {PhaseStart, uuid.New()}, {PhaseStart, uuid.New()}}
+	for _, key := range keys {
+		cache.begin(key)
+	}
+
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, keys[0])
+	_, owner := cache.begin(keys[2])
+	assert.False(t, owner)
+}
+
+func TestResultCache_disabled(t *testing.T) {
+	cache := newResultCache(0)
+	key := resultCacheKey{PhaseStart, uuid.New()}
+	cache.begin(key)
+
+	_, owner := cache.begin(key)
+	assert.True(t, owner)
+}
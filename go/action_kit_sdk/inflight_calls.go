@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// inflightCallTimeout is the maximum time the extension waits for canceled lifecycle calls to return before stopping the execution anyway.
+var inflightCallTimeout = 30 * time.Second
+
+// inflightCalls tracks the lifecycle calls currently running per execution, so that they can be canceled before the extension stops the execution.
+type inflightCalls struct {
+	mu         sync.Mutex
+	executions map[uuid.UUID]*inflightExecution
+}
+
+type inflightExecution struct {
+	calls map[*inflightCall]struct{}
+	// stopCause is set while the extension stops the execution, calls tracked meanwhile are canceled right away.
+	stopCause error
+	stops     int
+}
+
+type inflightCall struct {
+	phase  LifecyclePhase
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+}
+
+var inflight = &inflightCalls{executions: make(map[uuid.UUID]*inflightExecution)}
+
+// StoppedByExtensionError is the cause of the context of lifecycle calls canceled because the extension stops the execution,
+// e.g. on a heartbeat timeout or when the extension is terminated. Retrieve it using context.Cause(ctx).
+type StoppedByExtensionError struct {
+	Reason string
+}
+
+func (e *StoppedByExtensionError) Error() string {
+	return fmt.Sprintf("action was stopped by extension: %s", e.Reason)
+}
+
+// track registers a lifecycle call of the execution. The returned context is canceled when the extension stops the execution,
+// the returned function must be called once the call returned, further calls are ignored.
+// Stop calls are only canceled by cancelAll, stopping the execution waits for them instead.
+func (c *inflightCalls) track(ctx context.Context, executionId uuid.UUID, phase LifecyclePhase) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	call := &inflightCall{phase: phase, cancel: cancel, done: make(chan struct{})}
+
+	c.mu.Lock()
+	execution := c.executions[executionId]
+	if execution == nil {
+		execution = &inflightExecution{calls: make(map[*inflightCall]struct{})}
+		c.executions[executionId] = execution
+	}
+	execution.calls[call] = struct{}{}
+	stopCause := execution.stopCause
+	c.mu.Unlock()
+
+	if phase != PhaseStop {
+		if stopCause == nil {
+			if stopEvent := getStopEvent(executionId); stopEvent != nil {
+				stopCause = &StoppedByExtensionError{Reason: stopEvent.reason}
+			}
+		}
+		if stopCause != nil {
+			cancel(stopCause)
+		}
+	}
+
+	return ctx, sync.OnceFunc(func() {
+		cancel(nil)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(execution.calls, call)
+		if len(execution.calls) == 0 && execution.stops == 0 {
+			delete(c.executions, executionId)
+		}
+		close(call.done)
+	})
+}
+
+// cancel cancels the in-flight calls of the execution and waits until they returned, at most until the context is done or inflightCallTimeout elapsed.
+// Calls tracked until release is called are canceled right away. In-flight stop calls are awaited without canceling them.
+func (c *inflightCalls) cancel(ctx context.Context, executionId uuid.UUID, reason string) (release func()) {
+	cause := &StoppedByExtensionError{Reason: reason}
+
+	c.mu.Lock()
+	execution := c.executions[executionId]
+	if execution == nil {
+		execution = &inflightExecution{calls: make(map[*inflightCall]struct{})}
+		c.executions[executionId] = execution
+	}
+	execution.stopCause = cause
+	execution.stops++
+	calls := make([]*inflightCall, 0, len(execution.calls))
+	for call := range execution.calls {
+		calls = append(calls, call)
+	}
+	c.mu.Unlock()
+
+	release = func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		execution.stops--
+		if execution.stops == 0 {
+			execution.stopCause = nil
+			if len(execution.calls) == 0 {
+				delete(c.executions, executionId)
+			}
+		}
+	}
+
+	if len(calls) == 0 {
+		return release
+	}
+	ctx, cancel := context.WithTimeout(ctx, inflightCallTimeout)
+	defer cancel()
+	for _, call := range calls {
+		if call.phase == PhaseStop {
+			log.Info().
+				Str("executionId", executionId.String()).
+				Str("reason", reason).
+				Msg("waiting for in-flight stop call before stopping the action")
+			continue
+		}
+		log.Info().
+			Str("executionId", executionId.String()).
+			Str("reason", reason).
+			Msgf("canceling in-flight %s call before stopping the action", call.phase)
+		call.cancel(cause)
+	}
+	for _, call := range calls {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			log.Warn().
+				Str("executionId", executionId.String()).
+				Str("reason", reason).
+				Msgf("in-flight %s call did not return in time, stopping the action anyway", call.phase)
+		}
+	}
+	return release
+}
+
+// cancelAll cancels the in-flight prepare, start and status calls of all executions without waiting for them.
+// Running stop calls are not canceled, they may finish within the deadline of the sweep.
+func (c *inflightCalls) cancelAll(reason string) {
+	cause := &StoppedByExtensionError{Reason: reason}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, execution := range c.executions {
+		for call := range execution.calls {
+			if call.phase != PhaseStop {
+				call.cancel(cause)
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sidecarState struct {
+	Applied bool
+}
+
+// sidecarAction waits in Start until its context is canceled, like an action waiting for a sidecar container.
+type sidecarAction struct {
+	starting chan struct{}
+	stopped  chan sidecarState
+}
+
+func (a *sidecarAction) NewEmptyState() sidecarState {
+	return sidecarState{}
+}
+
+func (a *sidecarAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          "sidecar-action",
+		Label:       "Sidecar",
+		Description: "Action waiting for a sidecar",
+		Version:     "1.0.0",
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+	}
+}
+
+func (a *sidecarAction) Prepare(_ context.Context, _ *sidecarState, _ action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	return nil, nil
+}
+
+func (a *sidecarAction) Start(ctx context.Context, state *sidecarState) (*action_kit_api.StartResult, error) {
+	close(a.starting)
+	<-ctx.Done()
+	state.Applied = true
+	return nil, context.Cause(ctx)
+}
+
+func (a *sidecarAction) Stop(_ context.Context, state *sidecarState) (*action_kit_api.StopResult, error) {
+	a.stopped <- *state
+	return nil, nil
+}
+
+func TestStopAction_cancels_inflight_start(t *testing.T) {
+	useInmemoryStatePersister(t)
+	action := &sidecarAction{starting: make(chan struct{}), stopped: make(chan sidecarState, 1)}
+	adapter := newActionHttpAdapter[sidecarState](action, WithoutParameterValidation())
+	registeredActions[adapter.description.Id] = action
+	t.Cleanup(func() { delete(registeredActions, adapter.description.Id) })
+	executionId := uuid.New()
+
+	body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: executionId, Config: map[string]any{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+	var prepareResult action_kit_api.PrepareResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prepareResult))
+
+	body, err = json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: prepareResult.State})
+	require.NoError(t, err)
+	started := httptest.NewRecorder()
+	startReturned := make(chan struct{})
+	go func() {
+		defer close(startReturned)
+		adapter.handleStart(started, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	}()
+	<-action.starting
+
+	require.NoError(t, stopAction(context.Background(), executionId, "heartbeat timeout"))
+
+	select {
+	case <-startReturned:
+	default:
+		t.Fatal("stop did not wait for the in-flight start")
+	}
+	assert.Equal(t, sidecarState{Applied: true}, <-action.stopped, "stop uses the state persisted by the canceled start")
+	var startResult action_kit_api.StartResult
+	require.NoError(t, json.Unmarshal(started.Body.Bytes(), &startResult))
+	require.NotNil(t, startResult.Error)
+	assert.Equal(t, "action was stopped by extension: heartbeat timeout", *startResult.Error.Detail)
+}
+
+func TestInflightCalls_cancels_calls_tracked_while_stopping(t *testing.T) {
+	calls := &inflightCalls{executions: make(map[uuid.UUID]*inflightExecution)}
+	executionId := uuid.New()
+
+	release := calls.cancel(context.Background(), executionId, "signal")
+	ctx, done := calls.track(context.Background(), executionId, PhaseStatus)
+	done()
+	stopCtx, stopDone := calls.track(context.Background(), executionId, PhaseStop)
+	assert.NoError(t, stopCtx.Err(), "the stop of the extension itself is not canceled")
+	stopDone()
+	release()
+
+	var stopped *StoppedByExtensionError
+	require.ErrorAs(t, context.Cause(ctx), &stopped)
+	assert.Equal(t, "signal", stopped.Reason)
+	assert.Empty(t, calls.executions)
+}
+
+func TestInflightCalls_awaits_stop_without_canceling(t *testing.T) {
+	calls := &inflightCalls{executions: make(map[uuid.UUID]*inflightExecution)}
+	executionId := uuid.New()
+	ctx, done := calls.track(context.Background(), executionId, PhaseStop)
+
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		calls.cancel(context.Background(), executionId, "heartbeat timeout")()
+	}()
+	select {
+	case <-returned:
+		t.Fatal("cancel did not wait for the in-flight stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.NoError(t, ctx.Err())
+	done()
+	<-returned
+	assert.Empty(t, calls.executions)
+}
+
+func TestStopAction_waiting_for_inflight_calls_counts_against_stop_policy_deadline(t *testing.T) {
+	useInmemoryStatePersister(t)
+	action := &sidecarAction{starting: make(chan struct{}), stopped: make(chan sidecarState, 1)}
+	adapter := newActionHttpAdapter[sidecarState](action, WithoutParameterValidation(), WithStopPolicy(StopPolicy{AttemptTimeout: 50 * time.Millisecond, MaxAttempts: 1}))
+	registeredActions[adapter.description.Id] = action
+	registeredActionOptions[adapter.description.Id] = adapter.options
+	t.Cleanup(func() {
+		delete(registeredActions, adapter.description.Id)
+		delete(registeredActionOptions, adapter.description.Id)
+	})
+	executionId := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: adapter.description.Id, State: action_kit_api.ActionState{}}))
+	// a call ignoring the cancellation of its context
+	_, done := inflight.track(context.Background(), executionId, PhaseStart)
+	defer done()
+
+	begin := time.Now()
+	require.NoError(t, stopAction(context.Background(), executionId, "heartbeat timeout"))
+
+	assert.Less(t, time.Since(begin), time.Second, "stop must not wait for the inflightCallTimeout")
+	assert.Equal(t, sidecarState{}, <-action.stopped)
+}
+
+func TestInflightCalls_stops_waiting_after_timeout(t *testing.T) {
+	previous := inflightCallTimeout
+	inflightCallTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { inflightCallTimeout = previous })
+	calls := &inflightCalls{executions: make(map[uuid.UUID]*inflightExecution)}
+	executionId := uuid.New()
+	ctx, done := calls.track(context.Background(), executionId, PhaseStart)
+	defer done()
+
+	calls.cancel(context.Background(), executionId, "heartbeat timeout")()
+
+	assert.Error(t, ctx.Err(), "call is canceled even though it did not return")
+}
+
+func TestInflightCalls_cancel_all(t *testing.T) {
+	calls := &inflightCalls{executions: make(map[uuid.UUID]*inflightExecution)}
+	first, doneFirst := calls.track(context.Background(), uuid.New(), PhaseStart)
+	defer doneFirst()
+	second, doneSecond := calls.track(context.Background(), uuid.New(), PhaseStatus)
+	defer doneSecond()
+	stop, doneStop := calls.track(context.Background(), uuid.New(), PhaseStop)
+	defer doneStop()
+
+	calls.cancelAll("signal")
+
+	assert.Error(t, first.Err())
+	assert.Error(t, second.Err())
+	assert.NoError(t, stop.Err(), "running stops may finish within the deadline of the sweep")
+}
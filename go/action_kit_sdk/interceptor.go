@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// LifecyclePhase identifies the lifecycle call of an action.
+type LifecyclePhase string
+
+const (
+	PhasePrepare LifecyclePhase = "prepare"
+	PhaseStart   LifecyclePhase = "start"
+	PhaseStatus  LifecyclePhase = "status"
+	PhaseStop    LifecyclePhase = "stop"
+)
+
+// LifecycleCall describes a lifecycle call passed through the interceptors.
+type LifecycleCall struct {
+	Phase       LifecyclePhase
+	Description action_kit_api.ActionDescription
+	ExecutionId uuid.UUID
+	// Request is the parsed request body, i.e. *action_kit_api.PrepareActionRequestBody, *action_kit_api.StartActionRequestBody,
+	// *action_kit_api.ActionStatusRequestBody or *action_kit_api.StopActionRequestBody.
+	Request any
+	// Reason is set if the extension stops the execution itself, e.g. on a heartbeat timeout or a signal.
+	Reason string
+	// State is the state of the action after the call. Set once next returned.
+	State action_kit_api.ActionState
+	// Err is the error returned by the action. Set once next returned.
+	Err error
+}
+
+// LifecycleNext invokes the next interceptor or finally the action and returns the error reported to the agent.
+type LifecycleNext func(ctx context.Context) *action_kit_api.ActionKitError
+
+// Interceptor wraps the lifecycle calls of actions. It can inspect and modify the returned error, or short-circuit the call
+// by returning an error without calling next.
+type Interceptor func(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError
+
+var globalInterceptors []Interceptor
+
+// RegisterInterceptor adds an interceptor for the lifecycle calls of all actions. Global interceptors are called before the ones of the action.
+func RegisterInterceptor(interceptor Interceptor) {
+	globalInterceptors = append(globalInterceptors, interceptor)
+}
+
+// WithInterceptor adds an interceptor for the lifecycle calls of the action.
+func WithInterceptor(interceptor Interceptor) ActionOption {
+	return func(o *actionOptions) {
+		o.interceptors = append(o.interceptors, interceptor)
+	}
+}
+
+// intercept passes the lifecycle call through the interceptors and finally calls invoke.
+// Returns the error to be reported to the agent, errors returned by invoke are converted using failureTitle.
+func (a *actionHttpAdapter[T]) intercept(ctx context.Context, call *LifecycleCall, state *T, failureTitle string, invoke func(ctx context.Context) error) *action_kit_api.ActionKitError {
+	return interceptCall(ctx, call, a.description, a.options, state, failureTitle, invoke)
+}
+
+// interceptCall passes the lifecycle call of the described action through the global interceptors and the ones in options.
+// It is used for the calls of the agent as well as for the stops by the extension, state must be a pointer to the state of the action.
+func interceptCall(ctx context.Context, call *LifecycleCall, description action_kit_api.ActionDescription, options actionOptions, state any, failureTitle string, invoke func(ctx context.Context) error) *action_kit_api.ActionKitError {
+	call.Description = description
+	next := func(ctx context.Context) *action_kit_api.ActionKitError {
+		call.Err = invoke(ctx)
+		_ = options.encodeState(state, &call.State)
+		return toActionKitError(call.Err, failureTitle)
+	}
+
+	interceptors := append(append([]Interceptor{}, globalInterceptors...), options.interceptors...)
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context) *action_kit_api.ActionKitError {
+			return interceptor(ctx, call, inner)
+		}
+	}
+	return next(ctx)
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useGlobalInterceptor(t *testing.T, interceptor Interceptor) {
+	previous := globalInterceptors
+	t.Cleanup(func() { globalInterceptors = previous })
+	RegisterInterceptor(interceptor)
+}
+
+func startWithAdapter(t *testing.T, adapter *actionHttpAdapter[ExampleState], executionId uuid.UUID) action_kit_api.StartResult {
+	body, err := json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: action_kit_api.ActionState{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handleStart(w, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	var result action_kit_api.StartResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+func TestInterceptor_wraps_lifecycle_call(t *testing.T) {
+	useInmemoryStatePersister(t)
+	var order []string
+	var observed LifecycleCall
+	useGlobalInterceptor(t, func(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError {
+		order = append(order, "global")
+		return next(ctx)
+	})
+	action := NewExampleAction(make(chan Call, 10))
+	action.startError = errors.New("start failed")
+	adapter := newActionHttpAdapter[ExampleState](action, WithInterceptor(func(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError {
+		order = append(order, "action")
+		err := next(ctx)
+		observed = *call
+		return err
+	}))
+	executionId := uuid.New()
+	defer stopMonitorHeartbeat(executionId)
+
+	result := startWithAdapter(t, adapter, executionId)
+
+	assert.Equal(t, []string{"global", "action"}, order)
+	assert.Equal(t, PhaseStart, observed.Phase)
+	assert.Equal(t, adapter.description.Id, observed.Description.Id)
+	assert.Equal(t, executionId, observed.ExecutionId)
+	assert.Equal(t, executionId, observed.Request.(*action_kit_api.StartActionRequestBody).ExecutionId)
+	assert.Equal(t, "StartBeforeError", observed.State["TestStep"])
+	assert.EqualError(t, observed.Err, "start failed")
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Failed to start action.", result.Error.Title)
+}
+
+func TestInterceptor_short_circuits(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(calls), WithInterceptor(func(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError {
+		return &action_kit_api.ActionKitError{Title: "Experiment not allowed.", Status: new(action_kit_api.Failed)}
+	}))
+	executionId := uuid.New()
+	defer stopMonitorHeartbeat(executionId)
+
+	result := startWithAdapter(t, adapter, executionId)
+
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Experiment not allowed.", result.Error.Title)
+	assert.Equal(t, action_kit_api.Failed, *result.Error.Status)
+	assert.Empty(t, calls, "the action is not called")
+}
+
+func registerAdapter(t *testing.T, adapter *actionHttpAdapter[ExampleState], action any) {
+	registeredActions[adapter.description.Id] = action
+	registeredActionOptions[adapter.description.Id] = adapter.options
+	t.Cleanup(func() {
+		delete(registeredActions, adapter.description.Id)
+		delete(registeredActionOptions, adapter.description.Id)
+	})
+}
+
+func TestInterceptor_wraps_stop_by_extension(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	action := NewExampleAction(calls)
+	var observed LifecycleCall
+	adapter := newActionHttpAdapter[ExampleState](action, WithInterceptor(func(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError {
+		err := next(ctx)
+		observed = *call
+		return err
+	}))
+	registerAdapter(t, adapter, action)
+	executionId := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: adapter.description.Id, State: action_kit_api.ActionState{}}))
+
+	require.NoError(t, stopAction(context.Background(), executionId, "heartbeat timeout"))
+
+	assert.Equal(t, "Stop", (<-calls).Name)
+	assert.Equal(t, PhaseStop, observed.Phase)
+	assert.Equal(t, adapter.description.Id, observed.Description.Id)
+	assert.Equal(t, executionId, observed.Request.(*action_kit_api.StopActionRequestBody).ExecutionId)
+	assert.Equal(t, "heartbeat timeout", observed.Reason)
+	assert.NoError(t, observed.Err)
+}
+
+func TestInterceptor_short_circuits_stop_by_extension(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	action := NewExampleAction(calls)
+	adapter := newActionHttpAdapter[ExampleState](action, WithInterceptor(func(ctx context.Context, call *LifecycleCall, next LifecycleNext) *action_kit_api.ActionKitError {
+		return &action_kit_api.ActionKitError{Title: "Stop not allowed."}
+	}))
+	registerAdapter(t, adapter, action)
+	executionId := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: adapter.description.Id, State: action_kit_api.ActionState{}}))
+
+	err := stopAction(context.Background(), executionId, "heartbeat timeout")
+
+	var actionError *ActionError
+	require.ErrorAs(t, err, &actionError)
+	assert.Equal(t, "Stop not allowed.", actionError.Title)
+	assert.Equal(t, action_kit_api.Errored, actionError.Status)
+	assert.Empty(t, calls, "the action is not called")
+	_, err = statePersister.GetState(context.Background(), executionId)
+	assert.NoError(t, err, "the state is kept")
+}
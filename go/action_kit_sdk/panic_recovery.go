@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// actionPanic is returned by callRecovering if the called action panicked.
+type actionPanic struct {
+	value any
+	stack []byte
+}
+
+func (p *actionPanic) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+// callRecovering calls the given lifecycle function and converts a panic into an *actionPanic error.
+func callRecovering[R any](call func() (*R, error)) (result *R, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			result, err = nil, &actionPanic{value: value, stack: debug.Stack()}
+		}
+	}()
+	return call()
+}
+
+// handlePanic logs the panic, stops the execution for actions with stop and returns the error to report to the agent.
+// The stop is done before the response is written, so that the agent doesn't continue with a half-applied attack. A panic in prepare is not stopped,
+// the state of a failed prepare is not persisted and nothing was started yet. Panics in stop are reported as failed attempts by callStop.
+// The lifecycle call must not be tracked as in-flight anymore, otherwise the stop waits for the call itself.
+func (a *actionHttpAdapter[T]) handlePanic(p *actionPanic, executionId uuid.UUID, phase LifecyclePhase) *action_kit_api.ActionKitError {
+	log.Error().
+		Str("actionId", a.description.Id).
+		Str("executionId", executionId.String()).
+		Str("stack", string(p.stack)).
+		Msgf("action panicked in %s: %v", phase, p.value)
+
+	if phase != PhasePrepare && a.hasStop() {
+		StopAction(context.Background(), executionId, fmt.Sprintf("panic in %s", phase))
+	}
+
+	return &action_kit_api.ActionKitError{
+		Title:  fmt.Sprintf("Action panicked in %s: %v", phase, p.value),
+		Detail: new(string(p.stack)),
+		Status: new(action_kit_api.Errored),
+	}
+}
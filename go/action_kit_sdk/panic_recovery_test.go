@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingAction struct {
+	*ExampleAction
+}
+
+func (action *panickingAction) Start(_ context.Context, _ *ExampleState) (*action_kit_api.StartResult, error) {
+	panic("boom")
+}
+
+func TestCallRecovering(t *testing.T) {
+	result, err := callRecovering(func() (*action_kit_api.StartResult, error) {
+		panic("boom")
+	})
+	assert.Nil(t, result)
+	var panicked *actionPanic
+	require.ErrorAs(t, err, &panicked)
+	assert.Equal(t, "boom", panicked.value)
+	assert.Contains(t, string(panicked.stack), "TestCallRecovering")
+}
+
+func TestHandleStart_recovers_panic_and_stops_execution(t *testing.T) {
+	useInmemoryStatePersister(t)
+	calls := make(chan Call, 10)
+	action := &panickingAction{NewExampleAction(calls)}
+	adapter := newActionHttpAdapter[ExampleState](action)
+	registeredActions[adapter.description.Id] = action
+	t.Cleanup(func() { delete(registeredActions, adapter.description.Id) })
+
+	executionId := uuid.New()
+	state := action_kit_api.ActionState{"testStep": "Prepare"}
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: adapter.description.Id, State: state}))
+
+	body, err := json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: state})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handleStart(w, httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+
+	var result action_kit_api.StartResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Action panicked in start: boom", result.Error.Title)
+	assert.Equal(t, action_kit_api.Errored, *result.Error.Status)
+	assert.Contains(t, *result.Error.Detail, "panickingAction")
+	assert.Equal(t, state, *result.State)
+
+	require.Len(t, calls, 1, "stop is called before the response is written")
+	call := <-calls
+	assert.Equal(t, "Stop", call.Name)
+	assert.Equal(t, "Prepare", call.Args[0].(*ExampleState).TestStep, "stop is called with the last persisted state")
+	assert.NotNil(t, getStopEvent(executionId))
+}
+
+func (action *panickingAction) Prepare(_ context.Context, _ *ExampleState, _ action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	panic("boom")
+}
+
+
+func TestHandlePrepare_recovers_panic(t *testing.T) {
+	useInmemoryStatePersister(t)
+	adapter := newActionHttpAdapter[ExampleState](&panickingAction{NewExampleAction(make(chan Call, 10))}, WithoutParameterValidation())
+
+	executionId := uuid.New()
+	body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: executionId, Config: map[string]any{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+
+	var result action_kit_api.PrepareResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Action panicked in prepare: boom", result.Error.Title)
+	assert.Equal(t, action_kit_api.Errored, *result.Error.Status)
+	assert.Nil(t, getStopEvent(executionId), "a failed prepare is not stopped")
+}
+
+func TestCallStop_recovers_panic(t *testing.T) {
+	useInmemoryStatePersister(t)
+	_, err := callStop(context.Background(), DefaultStopPolicy, uuid.New(), "action", "test", func(ctx context.Context) (*action_kit_api.StopResult, error) {
+		panic("boom")
+	})
+	assert.EqualError(t, err, "panic: boom")
+}
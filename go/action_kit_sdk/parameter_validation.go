@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+type parameterViolation struct {
+	parameter string
+	message   string
+}
+
+func (v parameterViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.parameter, v.message)
+}
+
+// validateConfig checks the configuration passed to prepare against the parameter definitions of the action description.
+// All violations are collected, so that the user can fix them at once.
+func validateConfig(parameters []action_kit_api.ActionParameter, config map[string]any) []parameterViolation {
+	var violations []parameterViolation
+	for _, parameter := range parameters {
+		if parameter.Type == action_kit_api.ActionParameterTypeSeparator {
+			continue
+		}
+		value := config[parameter.Name]
+		if isEmptyValue(value) {
+			if parameter.Required != nil && *parameter.Required {
+				violations = append(violations, parameterViolation{parameter.Name, "is required"})
+			}
+			continue
+		}
+		if message := validateValue(parameter, value); message != "" {
+			violations = append(violations, parameterViolation{parameter.Name, message})
+		}
+	}
+	return violations
+}
+
+func validateValue(parameter action_kit_api.ActionParameter, value any) string {
+	switch parameter.Type {
+	case action_kit_api.ActionParameterTypeInteger, action_kit_api.ActionParameterTypePercentage, action_kit_api.ActionParameterTypeStressngWorkers:
+		number, ok := toNumber(value)
+		if !ok {
+			return fmt.Sprintf("must be a number, got %v", value)
+		}
+		if parameter.Type != action_kit_api.ActionParameterTypePercentage && number != math.Trunc(number) {
+			return fmt.Sprintf("must be an integer, got %v", value)
+		}
+		if parameter.MinValue != nil && number < float64(*parameter.MinValue) {
+			return fmt.Sprintf("must be >= %d, got %v", *parameter.MinValue, value)
+		}
+		if parameter.MaxValue != nil && number > float64(*parameter.MaxValue) {
+			return fmt.Sprintf("must be <= %d, got %v", *parameter.MaxValue, value)
+		}
+		if parameter.Type == action_kit_api.ActionParameterTypePercentage && parameter.MinValue == nil && parameter.MaxValue == nil && (number < 0 || number > 100) {
+			return fmt.Sprintf("must be between 0 and 100, got %v", value)
+		}
+	case action_kit_api.ActionParameterTypeDuration:
+		if _, err := toDuration(value); err != nil {
+			return err.Error()
+		}
+	case action_kit_api.ActionParameterTypeRegex:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("must be a string, got %T", value)
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			return fmt.Sprintf("is not a valid regular expression: %s", err)
+		}
+	case action_kit_api.ActionParameterTypeStringArray, action_kit_api.ActionParameterTypeString1:
+		values, ok := toStringSlice(value)
+		if !ok {
+			return fmt.Sprintf("must be an array of strings, got %v", value)
+		}
+		return validateOptions(parameter, values...)
+	case action_kit_api.ActionParameterTypeString, action_kit_api.ActionParameterTypeTextarea, action_kit_api.ActionParameterTypeUrl:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("must be a string, got %T", value)
+		}
+		return validateOptions(parameter, s)
+	}
+	return ""
+}
+
+// validateOptions checks the values against explicit options if the parameter only allows options, which is the default.
+// Options derived from target attributes are only known to the platform and are not checked.
+func validateOptions(parameter action_kit_api.ActionParameter, values ...string) string {
+	if (parameter.OptionsOnly != nil && !*parameter.OptionsOnly) || parameter.Options == nil {
+		return ""
+	}
+	var allowed []string
+	for _, option := range *parameter.Options {
+		switch o := option.(type) {
+		case action_kit_api.ExplicitParameterOption:
+			allowed = append(allowed, o.Value)
+		case *action_kit_api.ExplicitParameterOption:
+			allowed = append(allowed, o.Value)
+		default:
+			return ""
+		}
+	}
+	for _, value := range values {
+		if !slices.Contains(allowed, value) {
+			return fmt.Sprintf("must be one of [%s], got %q", strings.Join(allowed, ", "), value)
+		}
+	}
+	return ""
+}
+
+func toParameterValidationError(violations []parameterViolation) *action_kit_api.ActionKitError {
+	details := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		details = append(details, violation.String())
+	}
+	return &action_kit_api.ActionKitError{
+		Title:  "Invalid action configuration.",
+		Detail: new(strings.Join(details, "\n")),
+		Status: new(action_kit_api.Errored),
+	}
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toDuration converts a duration config value. The platform sends durations as milliseconds, duration strings like "10s" are accepted as well.
+func toDuration(value any) (time.Duration, error) {
+	if number, ok := toNumber(value); ok {
+		if number < 0 {
+			return 0, fmt.Errorf("must not be negative, got %v", value)
+		}
+		return time.Duration(number * float64(time.Millisecond)), nil
+	}
+	if s, ok := value.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("is not a valid duration: %q", s)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("must not be negative, got %v", value)
+		}
+		return d, nil
+	}
+	return 0, fmt.Errorf("must be a duration in milliseconds, got %T", value)
+}
+
+func toStringSlice(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	}
+	return nil, false
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	parameters := []action_kit_api.ActionParameter{
+		{Name: "duration", Type: action_kit_api.ActionParameterTypeDuration, Required: new(true)},
+		{Name: "workers", Type: action_kit_api.ActionParameterTypeInteger, MinValue: new(1), MaxValue: new(10)},
+		{Name: "percentage", Type: action_kit_api.ActionParameterTypePercentage},
+		{Name: "pattern", Type: action_kit_api.ActionParameterTypeRegex},
+		{Name: "mode", Type: action_kit_api.ActionParameterTypeString, OptionsOnly: new(true), Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{Label: "Fast", Value: "fast"},
+			action_kit_api.ExplicitParameterOption{Label: "Slow", Value: "slow"},
+		})},
+		{Name: "level", Type: action_kit_api.ActionParameterTypeString, Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{Label: "Low", Value: "low"},
+		})},
+		{Name: "label", Type: action_kit_api.ActionParameterTypeString, OptionsOnly: new(false), Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{Label: "Low", Value: "low"},
+		})},
+		{Name: "containers", Type: action_kit_api.ActionParameterTypeStringArray, OptionsOnly: new(true), Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ParameterOptionsFromTargetAttribute{Attribute: "container.name"},
+		})},
+		{Name: "-", Type: action_kit_api.ActionParameterTypeSeparator, Required: new(true)},
+	}
+
+	tests := []struct {
+		name     string
+		config   map[string]any
+		expected []parameterViolation
+	}{
+		{
+			name:   "valid config",
+			config: map[string]any{"duration": 10000.0, "workers": 4.0, "percentage": "50", "pattern": "^a.*$", "mode": "fast", "containers": []any{"any"}},
+		},
+		{
+			name:   "fractional percentage",
+			config: map[string]any{"duration": 1.0, "percentage": 12.5},
+		},
+		{
+			name:     "options only by default",
+			config:   map[string]any{"duration": 1.0, "level": "high", "label": "high"},
+			expected: []parameterViolation{{"level", "must be one of [low], got \"high\""}},
+		},
+		{
+			name:   "optional values may be missing",
+			config: map[string]any{"duration": "10s", "workers": nil, "mode": ""},
+		},
+		{
+			name:     "required value missing",
+			config:   map[string]any{"duration": nil},
+			expected: []parameterViolation{{"duration", "is required"}},
+		},
+		{
+			name:   "all violations are reported",
+			config: map[string]any{"duration": "soon", "workers": 11.0, "percentage": 101.0, "pattern": "(", "mode": "medium", "containers": "not-an-array"},
+			expected: []parameterViolation{
+				{"duration", "is not a valid duration: \"soon\""},
+				{"workers", "must be <= 10, got 11"},
+				{"percentage", "must be between 0 and 100, got 101"},
+				{"pattern", "is not a valid regular expression: error parsing regexp: missing closing ): `(`"},
+				{"mode", "must be one of [fast, slow], got \"medium\""},
+				{"containers", "must be an array of strings, got not-an-array"},
+			},
+		},
+		{
+			name:     "integer below minimum",
+			config:   map[string]any{"duration": 1.0, "workers": 0.0},
+			expected: []parameterViolation{{"workers", "must be >= 1, got 0"}},
+		},
+		{
+			name:     "fractional integer",
+			config:   map[string]any{"duration": 1.0, "workers": 1.5},
+			expected: []parameterViolation{{"workers", "must be an integer, got 1.5"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validateConfig(parameters, tt.config))
+		})
+	}
+}
+
+func TestToParameterValidationError(t *testing.T) {
+	err := toParameterValidationError([]parameterViolation{{"duration", "is required"}, {"workers", "must be >= 1, got 0"}})
+	assert.Equal(t, "Invalid action configuration.", err.Title)
+	assert.Equal(t, action_kit_api.Errored, *err.Status)
+	assert.Equal(t, "duration: is required\nworkers: must be >= 1, got 0", *err.Detail)
+}
+
+func TestToDuration(t *testing.T) {
+	d, err := toDuration(1500.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	d, err = toDuration("2m")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, d)
+
+	_, err = toDuration(-1.0)
+	assert.EqualError(t, err, "must not be negative, got -1")
+}
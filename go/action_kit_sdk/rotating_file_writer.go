@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFileWriter writes to a file and rotates it once it exceeds a maximum size.
+// Rotated files are renamed to <path>.1, <path>.2, ... up to the number of kept backups.
+type RotatingFileWriter struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFileWriter opens the file at path for appending. The file is rotated once it exceeds maxSize bytes, maxBackups rotated files are kept.
+func NewRotatingFileWriter(path string, maxSize int64, maxBackups int) (*RotatingFileWriter, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("maxSize must be positive")
+	}
+	w := &RotatingFileWriter{path: path, maxSize: maxSize, maxBackups: max(maxBackups, 0)}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file.
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file to the first backup and opens a new one. If the rotation fails, the current file is reopened,
+// so that later writes don't fail too, and the rotation is tried again with the next write.
+func (w *RotatingFileWriter) rotate() error {
+	err := w.file.Close()
+	w.file = nil
+	if err == nil {
+		err = w.moveToBackup()
+	}
+	if err != nil {
+		return errors.Join(err, w.open())
+	}
+	return w.open()
+}
+
+func (w *RotatingFileWriter) moveToBackup() error {
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(w.backupPath(i), w.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(w.path, w.backupPath(1))
+}
+
+func (w *RotatingFileWriter) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", w.path, index)
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/steadybit/extension-kit/extconversion"
+)
+
+const (
+	secretTag         = "secret"
+	secretValuePrefix = "secret:v1:"
+	maskedSecretValue = "***"
+)
+
+var (
+	stateEncryptionMu sync.Mutex
+	stateEncryption   cipher.AEAD
+	// secretFieldsByType caches the json names of the secret fields by state type.
+	secretFieldsByType = sync.Map{} // map[reflect.Type][]string
+)
+
+// SetStateEncryptionKey sets the AES key (16, 24 or 32 bytes) used to encrypt state fields tagged with `secret:"true"`.
+// Without a key, a random one is generated on first use, secret fields can't be decrypted after a restart of the extension then.
+// Registering an action with secret fields therefore fails if the states are persisted across restarts (see SetStatePersister) and no key is set.
+// Must be called before any action is registered.
+func SetStateEncryptionKey(key []byte) error {
+	aead, err := newStateEncryption(key)
+	if err != nil {
+		return err
+	}
+	stateEncryptionMu.Lock()
+	defer stateEncryptionMu.Unlock()
+	stateEncryption = aead
+	return nil
+}
+
+func newStateEncryption(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func getStateEncryption() (cipher.AEAD, error) {
+	stateEncryptionMu.Lock()
+	defer stateEncryptionMu.Unlock()
+	if stateEncryption == nil {
+		log.Warn().Msg("No state encryption key configured, generating a random one. Secret state fields can't be decrypted after a restart.")
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		aead, err := newStateEncryption(key)
+		if err != nil {
+			return nil, err
+		}
+		stateEncryption = aead
+	}
+	return stateEncryption, nil
+}
+
+// checkStateEncryption fails for states with secret fields if the state persister keeps the states across restarts and no key is configured.
+// The secret fields of executions left behind by a previous process couldn't be decrypted to stop them otherwise.
+func checkStateEncryption(state any) error {
+	if len(secretFields(state)) == 0 || state_persister.IsInmemory(statePersister) {
+		return nil
+	}
+	stateEncryptionMu.Lock()
+	defer stateEncryptionMu.Unlock()
+	if stateEncryption == nil {
+		return errors.New("state has secret fields and is persisted across restarts, but no state encryption key is set. Use SetStateEncryptionKey")
+	}
+	return nil
+}
+
+// toActionState converts the state of an action into an ActionState. Secret fields are encrypted.
+func toActionState(state any, out *action_kit_api.ActionState) error {
+	if err := extconversion.Convert(state, out); err != nil {
+		return err
+	}
+	for _, name := range secretFields(state) {
+		value, ok := (*out)[name]
+		if !ok {
+			continue
+		}
+		encrypted, err := encryptSecret(value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt secret field %s: %w", name, err)
+		}
+		(*out)[name] = encrypted
+	}
+	return nil
+}
+
+// fromActionState converts an ActionState into the state of an action. Secret fields are decrypted.
+func fromActionState(in action_kit_api.ActionState, state any) error {
+	if fields := secretFields(state); len(fields) > 0 && in != nil {
+		decrypted := make(action_kit_api.ActionState, len(in))
+		for key, value := range in {
+			decrypted[key] = value
+		}
+		for _, name := range fields {
+			value, err := decryptSecret(decrypted[name])
+			if err != nil {
+				return fmt.Errorf("failed to decrypt secret field %s: %w", name, err)
+			}
+			decrypted[name] = value
+		}
+		in = decrypted
+	}
+	return extconversion.Convert(in, state)
+}
+
+// MaskSecrets converts the state of an action into a map with all fields tagged with `secret:"true"` masked. Use it to log states.
+func MaskSecrets(state any) map[string]any {
+	var masked map[string]any
+	if err := extconversion.Convert(state, &masked); err != nil {
+		return nil
+	}
+	for _, name := range secretFields(state) {
+		if _, ok := masked[name]; ok {
+			masked[name] = maskedSecretValue
+		}
+	}
+	return masked
+}
+
+func encryptSecret(value any) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	aead, err := getStateEncryption()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return secretValuePrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// decryptSecret decrypts values encrypted by encryptSecret, other values are returned as is.
+func decryptSecret(value any) (any, error) {
+	encoded, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	encoded, ok = strings.CutPrefix(encoded, secretValuePrefix)
+	if !ok {
+		return value, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := getStateEncryption()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	var decrypted any
+	if err := json.Unmarshal(plaintext, &decrypted); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// secretFields returns the json names of the top-level fields of the state struct tagged with `secret:"true"`.
+func secretFields(state any) []string {
+	t := reflect.TypeOf(state)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		// e.g. a pointer to an interface holding the state
+		v := reflect.ValueOf(state)
+		for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+			v = v.Elem()
+		}
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return nil
+		}
+		t = v.Type()
+	}
+
+	if fields, ok := secretFieldsByType.Load(t); ok {
+		return fields.([]string)
+	}
+	var fields []string
+	for field := range t.Fields() {
+		if !field.IsExported() || field.Tag.Get(secretTag) != "true" {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		fields = append(fields, name)
+	}
+	secretFieldsByType.Store(t, fields)
+	return fields
+}
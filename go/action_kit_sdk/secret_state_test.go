@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secretState struct {
+	Host     string            `json:"host"`
+	Password string            `json:"password" secret:"true"`
+	Headers  map[string]string `secret:"true"`
+}
+
+type secretAction struct {
+	started chan secretState
+	stopped chan secretState
+}
+
+func (a *secretAction) NewEmptyState() secretState {
+	return secretState{}
+}
+
+func (a *secretAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          "secret-action",
+		Label:       "Secret",
+		Description: "Action with a secret state",
+		Version:     "1.0.0",
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+	}
+}
+
+func (a *secretAction) Prepare(_ context.Context, state *secretState, _ action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	state.Host = "db.local"
+	state.Password = "s3cr3t"
+	state.Headers = map[string]string{"Authorization": "Bearer token"}
+	return nil, nil
+}
+
+func (a *secretAction) Start(_ context.Context, state *secretState) (*action_kit_api.StartResult, error) {
+	a.started <- *state
+	return nil, nil
+}
+
+func (a *secretAction) Stop(_ context.Context, state *secretState) (*action_kit_api.StopResult, error) {
+	a.stopped <- *state
+	return nil, nil
+}
+
+func useStateEncryptionKey(t *testing.T) {
+	previous := stateEncryption
+	t.Cleanup(func() { stateEncryption = previous })
+	require.NoError(t, SetStateEncryptionKey([]byte("0123456789abcdef0123456789abcdef")))
+}
+
+func TestSecretState_encrypted_in_action_state(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useStateEncryptionKey(t)
+	action := &secretAction{started: make(chan secretState, 1), stopped: make(chan secretState, 1)}
+	adapter := newActionHttpAdapter[secretState](action, WithoutParameterValidation())
+	registeredActions[adapter.description.Id] = action
+	t.Cleanup(func() { delete(registeredActions, adapter.description.Id) })
+	executionId := uuid.New()
+
+	body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: executionId, Config: map[string]any{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+	assert.NotContains(t, w.Body.String(), "s3cr3t")
+	assert.NotContains(t, w.Body.String(), "Bearer token")
+	var prepareResult action_kit_api.PrepareResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prepareResult))
+	assert.Equal(t, "db.local", prepareResult.State["host"])
+	assert.True(t, strings.HasPrefix(prepareResult.State["password"].(string), secretValuePrefix))
+	assert.True(t, strings.HasPrefix(prepareResult.State["Headers"].(string), secretValuePrefix))
+
+	persisted, err := statePersister.GetState(context.Background(), executionId)
+	require.NoError(t, err)
+	assert.Equal(t, prepareResult.State["password"], persisted.State["password"])
+
+	body, err = json.Marshal(action_kit_api.StartActionRequestBody{ExecutionId: executionId, State: prepareResult.State})
+	require.NoError(t, err)
+	adapter.handleStart(httptest.NewRecorder(), httptest.NewRequest("POST", adapter.description.Start.Path, bytes.NewReader(body)), body)
+	expected := secretState{Host: "db.local", Password: "s3cr3t", Headers: map[string]string{"Authorization": "Bearer token"}}
+	assert.Equal(t, expected, <-action.started)
+
+	StopAction(context.Background(), executionId, "test")
+	assert.Equal(t, expected, <-action.stopped)
+}
+
+func TestSecretState_rejects_tampered_value(t *testing.T) {
+	useStateEncryptionKey(t)
+	var converted action_kit_api.ActionState
+	require.NoError(t, toActionState(secretState{Password: "s3cr3t"}, &converted))
+	converted["password"] = converted["password"].(string)[:len(converted["password"].(string))-4] + "AAAA"
+
+	var state secretState
+	assert.ErrorContains(t, fromActionState(converted, &state), "failed to decrypt secret field password")
+}
+
+func TestSecretState_plain_values_are_kept(t *testing.T) {
+	var state secretState
+	require.NoError(t, fromActionState(action_kit_api.ActionState{"password": "plain"}, &state))
+	assert.Equal(t, "plain", state.Password)
+}
+
+func TestMaskSecrets(t *testing.T) {
+	masked := MaskSecrets(&secretState{Host: "db.local", Password: "s3cr3t", Headers: map[string]string{"Authorization": "Bearer token"}})
+	assert.Equal(t, map[string]any{"host": "db.local", "password": "***", "Headers": "***"}, masked)
+}
+
+func TestSetStateEncryptionKey_invalid_length(t *testing.T) {
+	assert.Error(t, SetStateEncryptionKey([]byte("too short")))
+}
+
+func TestCheckStateEncryption(t *testing.T) {
+	useInmemoryStatePersister(t)
+	previous := stateEncryption
+	t.Cleanup(func() { stateEncryption = previous })
+	stateEncryption = nil
+
+	assert.NoError(t, checkStateEncryption(secretState{}), "in-memory states don't survive a restart")
+
+	persister, err := state_persister.NewFileStatePersister(t.TempDir())
+	require.NoError(t, err)
+	statePersister = persister
+	assert.NoError(t, checkStateEncryption(struct{ Host string }{}), "states without secret fields")
+	assert.ErrorContains(t, checkStateEncryption(secretState{}), "no state encryption key is set")
+
+	require.NoError(t, SetStateEncryptionKey([]byte("0123456789abcdef0123456789abcdef")))
+	assert.NoError(t, checkStateEncryption(secretState{}))
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package state_persister
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const stateFileSuffix = ".json"
+
+// NewFileStatePersister creates a StatePersister which stores one file per execution id in the given directory.
+// The states survive a restart of the extension, so that active actions can still be stopped afterwards.
+// Every write is done atomically (write to a temporary file, fsync, rename, fsync of the directory).
+func NewFileStatePersister(directory string) (StatePersister, error) {
+	if directory == "" {
+		return nil, errors.New("directory for persisted states must not be empty")
+	}
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s for persisted states: %w", directory, err)
+	}
+	return &fileStatePersister{directory: directory}, nil
+}
+
+type fileStatePersister struct {
+	directory string
+	mu        sync.RWMutex
+}
+
+func (p *fileStatePersister) PersistState(_ context.Context, state *PersistedState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode state for execution id %s: %w", state.ExecutionId, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tmp, err := os.CreateTemp(p.directory, state.ExecutionId.String()+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create state file for execution id %s: %w", state.ExecutionId, err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file for execution id %s: %w", state.ExecutionId, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync state file for execution id %s: %w", state.ExecutionId, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close state file for execution id %s: %w", state.ExecutionId, err)
+	}
+	if err := os.Rename(tmp.Name(), p.filename(state.ExecutionId)); err != nil {
+		return fmt.Errorf("failed to replace state file for execution id %s: %w", state.ExecutionId, err)
+	}
+	return p.syncDirectory()
+}
+
+func (p *fileStatePersister) GetExecutionIds(_ context.Context) ([]uuid.UUID, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	entries, err := os.ReadDir(p.directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persisted states in %s: %w", p.directory, err)
+	}
+
+	var ids []uuid.UUID
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), stateFileSuffix) {
+			continue
+		}
+		id, err := uuid.Parse(strings.TrimSuffix(entry.Name(), stateFileSuffix))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (p *fileStatePersister) GetState(_ context.Context, executionId uuid.UUID) (*PersistedState, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	data, err := os.ReadFile(p.filename(executionId))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("state not found for execution id %s", executionId)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read state file for execution id %s: %w", executionId, err)
+	}
+
+	var state PersistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode state file for execution id %s: %w", executionId, err)
+	}
+	return &state, nil
+}
+
+func (p *fileStatePersister) DeleteState(_ context.Context, executionId uuid.UUID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := os.Remove(p.filename(executionId)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete state file for execution id %s: %w", executionId, err)
+	}
+	return p.syncDirectory()
+}
+
+func (p *fileStatePersister) filename(executionId uuid.UUID) string {
+	return filepath.Join(p.directory, executionId.String()+stateFileSuffix)
+}
+
+// syncDirectory makes the rename/removal of a state file durable.
+func (p *fileStatePersister) syncDirectory() error {
+	dir, err := os.Open(p.directory)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = dir.Close()
+	}()
+	// Syncing a directory is not supported on all platforms (e.g. windows), the file itself was synced already.
+	if err := dir.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) && !errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("failed to sync directory %s: %w", p.directory, err)
+	}
+	return nil
+}
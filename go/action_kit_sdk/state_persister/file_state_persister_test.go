@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package state_persister
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStatePersister_basics(t *testing.T) {
+	persister, err := NewFileStatePersister(t.TempDir())
+	require.NoError(t, err)
+	exe1 := uuid.New()
+	exe2 := uuid.New()
+
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 1}})
+	require.NoError(t, err)
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe2, ActionId: "action-1", State: action_kit_api.ActionState{"test": 2}})
+	require.NoError(t, err)
+
+	executionIds, err := persister.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{exe1, exe2}, executionIds)
+
+	err = persister.DeleteState(context.Background(), exe1)
+	require.NoError(t, err)
+
+	executionIds, err = persister.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{exe2}, executionIds)
+
+	_, err = persister.GetState(context.Background(), exe1)
+	require.ErrorContains(t, err, "state not found")
+}
+
+func TestFileStatePersister_should_survive_restart(t *testing.T) {
+	dir := t.TempDir()
+	persister, err := NewFileStatePersister(dir)
+	require.NoError(t, err)
+	exe1 := uuid.New()
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": "value"}})
+	require.NoError(t, err)
+
+	restarted, err := NewFileStatePersister(dir)
+	require.NoError(t, err)
+	state, err := restarted.GetState(context.Background(), exe1)
+	require.NoError(t, err)
+	require.Equal(t, exe1, state.ExecutionId)
+	require.Equal(t, "action-1", state.ActionId)
+	require.Equal(t, "value", state.State["test"])
+}
+
+func TestFileStatePersister_should_update_existing_values(t *testing.T) {
+	persister, err := NewFileStatePersister(t.TempDir())
+	require.NoError(t, err)
+	exe1 := uuid.New()
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 1}})
+	require.NoError(t, err)
+
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 100}})
+	require.NoError(t, err)
+
+	executionIds, err := persister.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	require.Len(t, executionIds, 1)
+
+	state, err := persister.GetState(context.Background(), executionIds[0])
+	require.NoError(t, err)
+	require.InDelta(t, 100, state.State["test"], 0)
+}
+
+func TestFileStatePersister_should_ignore_foreign_files(t *testing.T) {
+	dir := t.TempDir()
+	persister, err := NewFileStatePersister(dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.json"), []byte("{}"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, uuid.NewString()+"-123.tmp"), []byte("{"), 0600))
+
+	err = persister.DeleteState(context.Background(), uuid.New())
+	require.NoError(t, err)
+
+	executionIds, err := persister.GetExecutionIds(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, executionIds)
+}
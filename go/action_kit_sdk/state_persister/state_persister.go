@@ -9,14 +9,33 @@ import (
 	"github.com/google/uuid"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"sync"
+	"time"
 )
 
 type PersistedState struct {
-	ExecutionId uuid.UUID
-	ActionId    string
-	State       action_kit_api.ActionState
+	ExecutionId uuid.UUID                  `json:"executionId"`
+	ActionId    string                     `json:"actionId"`
+	State       action_kit_api.ActionState `json:"state"`
+	// Target is the target of the execution as passed to prepare.
+	Target *action_kit_api.Target `json:"target,omitempty"`
+	// StartedAt is the time the execution was started, nil if it is only prepared.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// ExecutionContext is the execution context as passed to prepare.
+	ExecutionContext *action_kit_api.ExecutionContext `json:"executionContext,omitempty"`
+	// Leftover is set if the execution could not be stopped and needs manual cleanup.
+	Leftover *Leftover `json:"leftover,omitempty"`
+	// ProcessId identifies the process of the extension which persisted the state, empty for states persisted by older versions.
+	ProcessId string `json:"processId,omitempty"`
 }
 
+// Leftover describes the failed stop of an execution.
+type Leftover struct {
+	Reason    string    `json:"reason"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// StatePersister stores the state of active actions, so that they can be stopped by the extension itself (e.g. on heartbeat timeouts or signals).
 type StatePersister interface {
 	PersistState(ctx context.Context, state *PersistedState) error
 	GetExecutionIds(ctx context.Context) ([]uuid.UUID, error)
@@ -24,10 +43,17 @@ type StatePersister interface {
 	DeleteState(ctx context.Context, executionId uuid.UUID) error
 }
 
+// NewInmemoryStatePersister creates a StatePersister which keeps the states in memory. States are lost when the extension restarts.
 func NewInmemoryStatePersister() StatePersister {
 	return &inmemoryStatePersister{states: sync.Map{}}
 }
 
+// IsInmemory reports whether the persister keeps the states in memory only, i.e. they don't survive a restart of the extension.
+func IsInmemory(persister StatePersister) bool {
+	_, ok := persister.(*inmemoryStatePersister)
+	return ok
+}
+
 type inmemoryStatePersister struct {
 	states sync.Map // map[uuid.UUID]*PersistedState
 }
@@ -21,9 +21,9 @@ func TestInmemoryStatePersister_basics(t *testing.T) {
 	exe1 := uuid.New()
 	exe2 := uuid.New()
 
-	err := persister.PersistState(context.Background(), &PersistedState{exe1, "action-1", action_kit_api.ActionState{"test": 1}})
+	err := persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 1}})
 	require.NoError(t, err)
-	err = persister.PersistState(context.Background(), &PersistedState{exe2, "action-1", action_kit_api.ActionState{"test": 2}})
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe2, ActionId: "action-1", State: action_kit_api.ActionState{"test": 2}})
 	require.NoError(t, err)
 
 	executionIds, err := persister.GetExecutionIds(context.Background())
@@ -41,7 +41,7 @@ func TestInmemoryStatePersister_basics(t *testing.T) {
 func TestInmemoryStatePersister_should_ignore_not_found(t *testing.T) {
 	persister := NewInmemoryStatePersister()
 	exe1 := uuid.New()
-	err := persister.PersistState(context.Background(), &PersistedState{exe1, "action-1", action_kit_api.ActionState{"test": 1}})
+	err := persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 1}})
 	require.NoError(t, err)
 
 	err = persister.DeleteState(context.Background(), uuid.New())
@@ -55,10 +55,10 @@ func TestInmemoryStatePersister_should_ignore_not_found(t *testing.T) {
 func TestInmemoryStatePersister_should_update_existing_values(t *testing.T) {
 	persister := NewInmemoryStatePersister()
 	exe1 := uuid.New()
-	err := persister.PersistState(context.Background(), &PersistedState{exe1, "action-1", action_kit_api.ActionState{"test": 1}})
+	err := persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 1}})
 	require.NoError(t, err)
 
-	err = persister.PersistState(context.Background(), &PersistedState{exe1, "action-1", action_kit_api.ActionState{"test": 100}})
+	err = persister.PersistState(context.Background(), &PersistedState{ExecutionId: exe1, ActionId: "action-1", State: action_kit_api.ActionState{"test": 100}})
 	require.NoError(t, err)
 
 	executionIds, err := persister.GetExecutionIds(context.Background())
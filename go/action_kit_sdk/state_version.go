@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// StateVersionKey is the key of the ActionState holding the schema version of the state.
+// States without the key, e.g. created by an older version of the SDK, have version 0.
+const StateVersionKey = "_stateVersion"
+
+// StateMigration migrates an ActionState to the next version. Fields tagged with `secret:"true"` are still encrypted.
+type StateMigration func(state action_kit_api.ActionState) (action_kit_api.ActionState, error)
+
+// WithStateVersion sets the schema version of the action's state, which is stamped into every ActionState. Without it, states have version 0 and aren't stamped.
+// Increase it whenever the state struct changes in an incompatible way and register a migration from the previous version with WithStateMigration.
+func WithStateVersion(version int) ActionOption {
+	return func(o *actionOptions) {
+		o.stateVersion = version
+	}
+}
+
+// WithStateMigration registers a migration of the action's state from the given version to the next one.
+// Migrations are applied one after another before Start, Status and Stop, until the state has the version configured with WithStateVersion.
+func WithStateMigration(from int, migration StateMigration) ActionOption {
+	return func(o *actionOptions) {
+		if o.stateMigrations == nil {
+			o.stateMigrations = make(map[int]StateMigration)
+		}
+		o.stateMigrations[from] = migration
+	}
+}
+
+// encodeState converts the state into an ActionState, stamped with the state version if one is configured with WithStateVersion.
+func (o actionOptions) encodeState(state any, out *action_kit_api.ActionState) error {
+	if err := toActionState(state, out); err != nil {
+		return err
+	}
+	if o.stateVersion == 0 {
+		return nil
+	}
+	if *out == nil {
+		*out = action_kit_api.ActionState{}
+	}
+	(*out)[StateVersionKey] = o.stateVersion
+	return nil
+}
+
+// decodeState migrates the ActionState to the current state version and converts it into the state.
+func (o actionOptions) decodeState(in action_kit_api.ActionState, state any) error {
+	migrated, err := o.migrateState(in)
+	if err != nil {
+		return err
+	}
+	return fromActionState(migrated, state)
+}
+
+func (o actionOptions) migrateState(in action_kit_api.ActionState) (action_kit_api.ActionState, error) {
+	version, err := stateVersionOf(in)
+	if err != nil {
+		return nil, err
+	}
+	if version > o.stateVersion {
+		return nil, fmt.Errorf("state version %d is newer than the supported version %d", version, o.stateVersion)
+	}
+	if version == o.stateVersion {
+		return in, nil
+	}
+
+	state := maps.Clone(in)
+	for ; version < o.stateVersion; version++ {
+		migration, ok := o.stateMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration of the state from version %d to %d registered", version, o.stateVersion)
+		}
+		if state, err = migration(state); err != nil {
+			return nil, fmt.Errorf("failed to migrate the state from version %d to %d: %w", version, version+1, err)
+		}
+	}
+	if state == nil {
+		state = action_kit_api.ActionState{}
+	}
+	state[StateVersionKey] = o.stateVersion
+	return state, nil
+}
+
+func stateVersionOf(state action_kit_api.ActionState) (int, error) {
+	switch version := state[StateVersionKey].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return version, nil
+	case float64:
+		return int(version), nil
+	case json.Number:
+		v, err := version.Int64()
+		return int(v), err
+	default:
+		return 0, fmt.Errorf("invalid state version %v", version)
+	}
+}
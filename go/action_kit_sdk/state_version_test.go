@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renameField(from, to string) StateMigration {
+	return func(state action_kit_api.ActionState) (action_kit_api.ActionState, error) {
+		state[to] = state[from]
+		delete(state, from)
+		return state, nil
+	}
+}
+
+func TestStateVersion_stamped_into_action_state(t *testing.T) {
+	useInmemoryStatePersister(t)
+	adapter := newActionHttpAdapter[ExampleState](NewExampleAction(make(chan Call, 10)), WithoutParameterValidation(), WithStateVersion(3))
+
+	body, err := json.Marshal(action_kit_api.PrepareActionRequestBody{ExecutionId: uuid.New(), Config: map[string]any{}})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	adapter.handlePrepare(w, httptest.NewRequest("POST", adapter.description.Prepare.Path, bytes.NewReader(body)), body)
+
+	var result action_kit_api.PrepareResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, float64(3), result.State[StateVersionKey])
+}
+
+func TestStateVersion_not_stamped_without_version(t *testing.T) {
+	var out action_kit_api.ActionState
+	require.NoError(t, newActionOptions().encodeState(map[string]any{"Step": "Prepare"}, &out))
+	assert.Equal(t, action_kit_api.ActionState{"Step": "Prepare"}, out)
+}
+
+func TestStateVersion_applies_migrations(t *testing.T) {
+	options := newActionOptions(
+		WithStateVersion(2),
+		WithStateMigration(0, renameField("Step", "Phase")),
+		WithStateMigration(1, renameField("Phase", "TestStep")),
+	)
+	old := action_kit_api.ActionState{"Duration": "10s", "Step": "Prepare"}
+
+	var state ExampleState
+	require.NoError(t, options.decodeState(old, &state))
+
+	assert.Equal(t, ExampleState{Duration: "10s", TestStep: "Prepare"}, state)
+	assert.Equal(t, "Prepare", old["Step"], "the original state is not modified")
+}
+
+func TestStateVersion_current_version_is_not_migrated(t *testing.T) {
+	options := newActionOptions(WithStateVersion(1), WithStateMigration(0, func(state action_kit_api.ActionState) (action_kit_api.ActionState, error) {
+		return nil, errors.New("must not be called")
+	}))
+
+	var state ExampleState
+	require.NoError(t, options.decodeState(action_kit_api.ActionState{StateVersionKey: float64(1), "TestStep": "Start"}, &state))
+	assert.Equal(t, "Start", state.TestStep)
+}
+
+func TestStateVersion_missing_migration_path(t *testing.T) {
+	options := newActionOptions(WithStateVersion(3), WithStateMigration(2, renameField("Step", "TestStep")))
+
+	var state ExampleState
+	err := options.decodeState(action_kit_api.ActionState{StateVersionKey: float64(1)}, &state)
+	assert.EqualError(t, err, "no migration of the state from version 1 to 3 registered")
+}
+
+func TestStateVersion_newer_version(t *testing.T) {
+	options := newActionOptions(WithStateVersion(1))
+
+	var state ExampleState
+	err := options.decodeState(action_kit_api.ActionState{StateVersionKey: float64(2)}, &state)
+	assert.EqualError(t, err, "state version 2 is newer than the supported version 1")
+}
+
+func TestStateVersion_failing_migration(t *testing.T) {
+	options := newActionOptions(WithStateVersion(1), WithStateMigration(0, func(state action_kit_api.ActionState) (action_kit_api.ActionState, error) {
+		return nil, errors.New("unknown mode")
+	}))
+
+	var state ExampleState
+	err := options.decodeState(action_kit_api.ActionState{}, &state)
+	assert.EqualError(t, err, "failed to migrate the state from version 0 to 1: unknown mode")
+}
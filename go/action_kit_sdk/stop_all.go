@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// StopAllOptions controls how StopAllActiveActions stops the active executions.
+type StopAllOptions struct {
+	// Concurrency is the number of executions stopped in parallel.
+	Concurrency int
+	// Deadline for the whole sweep, including waiting for in-flight starts. Executions not stopped until then are skipped, also if their stop is still running.
+	Deadline time.Duration
+}
+
+// DefaultStopAllOptions stops 10 executions in parallel and finishes within 25s, before the default termination grace period of Kubernetes expires.
+var DefaultStopAllOptions = StopAllOptions{Concurrency: 10, Deadline: 25 * time.Second}
+
+var stopAllOptions = DefaultStopAllOptions
+
+// StoppedExecution describes an execution handled by StopAllActiveActions.
+type StoppedExecution struct {
+	ExecutionId uuid.UUID
+	ActionId    string
+	Err         error
+}
+
+// StopAllReport is the outcome of StopAllActiveActions.
+type StopAllReport struct {
+	// Stopped contains the executions which were stopped successfully.
+	Stopped []StoppedExecution
+	// Failed contains the executions for which Stop returned an error or the state could not be loaded.
+	Failed []StoppedExecution
+	// Skipped contains the executions whose action is not registered or which were not stopped before the deadline.
+	Skipped []StoppedExecution
+}
+
+// SetStopAllOptions replaces the options used by StopAllActiveActions. Zero values fall back to DefaultStopAllOptions.
+func SetStopAllOptions(options StopAllOptions) {
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultStopAllOptions.Concurrency
+	}
+	if options.Deadline <= 0 {
+		options.Deadline = DefaultStopAllOptions.Deadline
+	}
+	stopAllOptions = options
+}
+
+// startGate tracks in-flight starts, so that a stop sweep only begins after they have finished and picks up their executions.
+type startGate struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+}
+
+var starts = &startGate{}
+
+// enter registers an in-flight start. Returns false while a stop sweep is running, the start must be rejected then.
+func (g *startGate) enter() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.inFlight.Add(1)
+	return true
+}
+
+func (g *startGate) leave() {
+	g.inFlight.Done()
+}
+
+// close rejects further starts and waits for the in-flight ones, at most until the context is done.
+func (g *startGate) close(ctx context.Context) {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn().Msg("in-flight starts did not finish in time, stopping active actions anyway")
+	}
+}
+
+func (g *startGate) open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = false
+}
+
+// runningStops tracks the stops of all sweeps, including those still running after the deadline of their sweep.
+var runningStops sync.WaitGroup
+
+// StopAllActiveActions stops all active executions in parallel. Starts are rejected during the sweep and accepted again afterwards,
+// in-flight starts are awaited before the sweep. Concurrency and deadline are set with SetStopAllOptions.
+func StopAllActiveActions(reason string) StopAllReport {
+	return stopAllActiveActions(reason, true)
+}
+
+// stopAllActiveActions stops all active executions. If resumeStarts is not set, starts are rejected for good after the sweep,
+// used by the signal handlers while the extension terminates.
+func stopAllActiveActions(reason string, resumeStarts bool) StopAllReport {
+	options := stopAllOptions
+	ctx, cancel := context.WithTimeout(context.Background(), options.Deadline)
+	defer cancel()
+
+	inflight.cancelAll(reason)
+	starts.close(ctx)
+	if resumeStarts {
+		defer starts.open()
+	}
+
+	report := StopAllReport{}
+	executionIds, err := statePersister.GetExecutionIds(ctx)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to load active action states")
+		return report
+	}
+	if len(executionIds) == 0 {
+		clearExecutionSinks()
+		return report
+	}
+	log.Warn().Str("reason", reason).Int("executions", len(executionIds)).Msg("stopping active actions")
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	// executions being stopped, reported as skipped if their stop did not return before the deadline
+	pending := make(map[uuid.UUID]StoppedExecution)
+	finished := false
+	semaphore := make(chan struct{}, max(options.Concurrency, 1))
+	for _, executionId := range executionIds {
+		execution := StoppedExecution{ExecutionId: executionId}
+		if persistedState, err := statePersister.GetState(ctx, executionId); err == nil {
+			execution.ActionId = persistedState.ActionId
+		}
+
+		acquired := false
+		select {
+		case semaphore <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// select picks randomly if a slot got free at the deadline
+			if acquired {
+				<-semaphore
+			}
+			mu.Lock()
+			execution.Err = ctx.Err()
+			report.Skipped = append(report.Skipped, execution)
+			mu.Unlock()
+			continue
+		}
+
+		mu.Lock()
+		pending[executionId] = execution
+		mu.Unlock()
+		wg.Add(1)
+		runningStops.Add(1)
+		go func() {
+			defer runningStops.Done()
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			err := stopAction(ctx, execution.ExecutionId, reason)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if finished {
+				return
+			}
+			delete(pending, execution.ExecutionId)
+			execution.Err = err
+			switch {
+			case errors.Is(err, errActionNotRegistered):
+				report.Skipped = append(report.Skipped, execution)
+			case err != nil:
+				report.Failed = append(report.Failed, execution)
+			default:
+				report.Stopped = append(report.Stopped, execution)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// stops ignoring the context must not block the termination of the extension
+	}
+	mu.Lock()
+	finished = true
+	for _, execution := range pending {
+		execution.Err = ctx.Err()
+		report.Skipped = append(report.Skipped, execution)
+	}
+	mu.Unlock()
+	// executions of actions without stop are not persisted, their execution sinks are released as well
+	clearExecutionSinks()
+
+	report.log(reason)
+	return report
+}
+
+func (r StopAllReport) log(reason string) {
+	for _, execution := range r.Failed {
+		log.Error().Err(execution.Err).
+			Str("actionId", execution.ActionId).
+			Str("executionId", execution.ExecutionId.String()).
+			Msg("failed to stop active action")
+	}
+	for _, execution := range r.Skipped {
+		log.Warn().Err(execution.Err).
+			Str("actionId", execution.ActionId).
+			Str("executionId", execution.ExecutionId.String()).
+			Msg("skipped stopping active action")
+	}
+	log.Info().
+		Str("reason", reason).
+		Int("stopped", len(r.Stopped)).
+		Int("failed", len(r.Failed)).
+		Int("skipped", len(r.Skipped)).
+		Msg("stopped active actions")
+}
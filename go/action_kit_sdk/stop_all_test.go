@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slowStopAction struct {
+	*ExampleAction
+	delay         time.Duration
+	ignoreContext bool
+}
+
+func (action *slowStopAction) Stop(ctx context.Context, _ *ExampleState) (*action_kit_api.StopResult, error) {
+	if action.ignoreContext {
+		time.Sleep(action.delay)
+		return nil, nil
+	}
+	select {
+	case <-time.After(action.delay):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func useStopAllOptions(t *testing.T, options StopAllOptions) {
+	previous := stopAllOptions
+	t.Cleanup(func() {
+		// stops which did not return before the deadline must not outlive the test
+		runningStops.Wait()
+		stopAllOptions = previous
+		// the signal handlers keep rejecting starts
+		starts.open()
+	})
+	SetStopAllOptions(options)
+}
+
+func registerSlowStopAction(t *testing.T, delay time.Duration) string {
+	action := &slowStopAction{ExampleAction: NewExampleAction(nil), delay: delay}
+	actionId := action.Describe().Id
+	registeredActions[actionId] = action
+	t.Cleanup(func() { delete(registeredActions, actionId) })
+	return actionId
+}
+
+func persistExecutions(t *testing.T, actionId string, count int) {
+	for range count {
+		require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: uuid.New(), ActionId: actionId, State: action_kit_api.ActionState{}}))
+	}
+}
+
+func TestStopAllActiveActions_stops_in_parallel(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useStopAllOptions(t, StopAllOptions{Concurrency: 5})
+	actionId := registerSlowStopAction(t, 200*time.Millisecond)
+	persistExecutions(t, actionId, 5)
+	persistExecutions(t, "unregistered-action", 1)
+
+	begin := time.Now()
+	report := StopAllActiveActions("test")
+
+	assert.Less(t, time.Since(begin), 600*time.Millisecond)
+	assert.Len(t, report.Stopped, 5)
+	assert.Empty(t, report.Failed)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, "unregistered-action", report.Skipped[0].ActionId)
+}
+
+func TestStopAllActiveActions_respects_deadline(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useStopAllOptions(t, StopAllOptions{Concurrency: 1, Deadline: 100 * time.Millisecond})
+	actionId := registerSlowStopAction(t, time.Hour)
+	persistExecutions(t, actionId, 3)
+
+	begin := time.Now()
+	report := StopAllActiveActions("test")
+
+	assert.Less(t, time.Since(begin), 2*time.Second)
+	assert.Empty(t, report.Stopped)
+	// the running stop fails with the deadline or is skipped if it did not return in time
+	assert.Len(t, append(report.Failed, report.Skipped...), 3)
+}
+
+func TestStopAllActiveActions_does_not_wait_for_stops_beyond_deadline(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useStopAllOptions(t, StopAllOptions{Concurrency: 2, Deadline: 100 * time.Millisecond})
+	action := &slowStopAction{ExampleAction: NewExampleAction(nil), delay: time.Second, ignoreContext: true}
+	registeredActions[action.Describe().Id] = action
+	t.Cleanup(func() { delete(registeredActions, action.Describe().Id) })
+	persistExecutions(t, action.Describe().Id, 2)
+
+	begin := time.Now()
+	report := StopAllActiveActions("test")
+
+	assert.Less(t, time.Since(begin), 500*time.Millisecond)
+	assert.Empty(t, report.Stopped)
+	assert.Empty(t, report.Failed)
+	require.Len(t, report.Skipped, 2)
+	for _, execution := range report.Skipped {
+		assert.ErrorIs(t, execution.Err, context.DeadlineExceeded)
+	}
+}
+
+func TestStopAllActiveActions_keeps_rejecting_starts(t *testing.T) {
+	useInmemoryStatePersister(t)
+	useStopAllOptions(t, DefaultStopAllOptions)
+
+	StopAllActiveActions("test")
+	assert.True(t, starts.enter(), "starts are accepted again if the extension keeps running")
+	starts.leave()
+
+	stopAllActiveActions("received signal SIGTERM", false)
+	assert.False(t, starts.enter(), "starts are rejected while the extension terminates")
+}
+
+func TestStartGate(t *testing.T) {
+	gate := &startGate{}
+	require.True(t, gate.enter())
+
+	closed := make(chan struct{})
+	go func() {
+		gate.close(context.Background())
+		close(closed)
+	}()
+
+	assert.Eventually(t, func() bool {
+		gate.mu.Lock()
+		defer gate.mu.Unlock()
+		return gate.closed
+	}, time.Second, time.Millisecond)
+	assert.False(t, gate.enter(), "starts are rejected during the sweep")
+
+	select {
+	case <-closed:
+		assert.Fail(t, "close must wait for in-flight starts")
+	case <-time.After(50 * time.Millisecond):
+	}
+	gate.leave()
+	<-closed
+
+	gate.open()
+	assert.True(t, gate.enter())
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+)
+
+// StopPolicy controls how the SDK calls Stop of an action, both for stop requests of the agent and for stops initiated by the extension
+// (heartbeat timeout, signals).
+type StopPolicy struct {
+	// AttemptTimeout is the deadline of the context passed to each Stop attempt. Zero means no deadline.
+	AttemptTimeout time.Duration
+	// MaxAttempts is the number of times Stop is called until it succeeds. Values below 1 are treated as 1.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt, it is doubled for each further attempt.
+	Backoff time.Duration
+}
+
+// DefaultStopPolicy is used for actions registered without WithStopPolicy. Stop is called once without a deadline.
+var DefaultStopPolicy = StopPolicy{
+	MaxAttempts: 1,
+}
+
+// WithStopPolicy overrides the DefaultStopPolicy for the action.
+func WithStopPolicy(policy StopPolicy) ActionOption {
+	return func(o *actionOptions) {
+		o.stopPolicy = &policy
+	}
+}
+
+// deadline returns the maximum duration of all attempts including the backoffs, 0 if the attempts have no deadline.
+func (p StopPolicy) deadline() time.Duration {
+	if p.AttemptTimeout <= 0 {
+		return 0
+	}
+	attempts := max(p.MaxAttempts, 1)
+	deadline := time.Duration(attempts) * p.AttemptTimeout
+	backoff := p.Backoff
+	for attempt := 1; attempt < attempts; attempt++ {
+		deadline += backoff
+		backoff *= 2
+	}
+	return deadline
+}
+
+func (o actionOptions) getStopPolicy() StopPolicy {
+	if o.stopPolicy != nil {
+		return *o.stopPolicy
+	}
+	return DefaultStopPolicy
+}
+
+// callStop calls stop according to the policy until it succeeds, the attempts are exhausted or the context is done.
+// If all attempts fail, the execution is recorded as leftover in its persisted state.
+func callStop(ctx context.Context, policy StopPolicy, executionId uuid.UUID, actionId string, reason string, stop func(ctx context.Context) (*action_kit_api.StopResult, error)) (*action_kit_api.StopResult, error) {
+	attempts := max(policy.MaxAttempts, 1)
+	backoff := policy.Backoff
+
+	var result *action_kit_api.StopResult
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, err = callStopAttempt(ctx, policy.AttemptTimeout, stop)
+		if err == nil {
+			clearLeftover(ctx, executionId)
+			return result, nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		log.Warn().
+			Str("actionId", actionId).
+			Str("executionId", executionId.String()).
+			Int("attempt", attempt).
+			Dur("backoff", backoff).
+			Err(err).
+			Msg("failed stopping action, retrying")
+
+		select {
+		case <-ctx.Done():
+			attempts = attempt
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+
+	markAsLeftover(ctx, executionId, reason, err)
+	log.Error().
+		Str("actionId", actionId).
+		Str("executionId", executionId.String()).
+		Str("reason", reason).
+		Int("attempts", attempts).
+		Err(err).
+		Msg("failed stopping action, manual cleanup required")
+	return result, err
+}
+
+func callStopAttempt(ctx context.Context, timeout time.Duration, stop func(ctx context.Context) (*action_kit_api.StopResult, error)) (*action_kit_api.StopResult, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	result, err := callRecovering(func() (*action_kit_api.StopResult, error) {
+		return stop(ctx)
+	})
+	var panicked *actionPanic
+	if errors.As(err, &panicked) {
+		log.Error().Str("stack", string(panicked.stack)).Msgf("action panicked in stop: %v", panicked.value)
+	}
+	return result, err
+}
+
+// Leftover is an execution which could not be reverted by the extension and needs manual cleanup.
+type Leftover struct {
+	ExecutionId uuid.UUID
+	ActionId    string
+	Reason      string
+	Error       string
+	Timestamp   time.Time
+}
+
+func (l Leftover) message() string {
+	return fmt.Sprintf("Reverting the action failed, manual cleanup required: %s", l.Error)
+}
+
+// GetLeftovers returns the executions which could not be reverted by the extension. Leftovers are kept in the persisted state of the execution,
+// they are stopped again when the action is registered after a restart and removed once they were stopped successfully.
+func GetLeftovers() []Leftover {
+	ctx := context.Background()
+	executionIds, err := statePersister.GetExecutionIds(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed loading active executions")
+		return nil
+	}
+	var result []Leftover
+	for _, executionId := range executionIds {
+		if leftover := getLeftover(ctx, executionId); leftover != nil {
+			result = append(result, *leftover)
+		}
+	}
+	return result
+}
+
+func getLeftover(ctx context.Context, executionId uuid.UUID) *Leftover {
+	persistedState, err := statePersister.GetState(ctx, executionId)
+	if err != nil || persistedState.Leftover == nil {
+		return nil
+	}
+	return &Leftover{
+		ExecutionId: persistedState.ExecutionId,
+		ActionId:    persistedState.ActionId,
+		Reason:      persistedState.Leftover.Reason,
+		Error:       persistedState.Leftover.Error,
+		Timestamp:   persistedState.Leftover.Timestamp,
+	}
+}
+
+func markAsLeftover(ctx context.Context, executionId uuid.UUID, reason string, err error) {
+	leftover := &state_persister.Leftover{Reason: reason, Timestamp: time.Now()}
+	if err != nil {
+		leftover.Error = err.Error()
+	}
+	updateLeftover(ctx, executionId, leftover)
+}
+
+func clearLeftover(ctx context.Context, executionId uuid.UUID) {
+	updateLeftover(ctx, executionId, nil)
+}
+
+func updateLeftover(ctx context.Context, executionId uuid.UUID, leftover *state_persister.Leftover) {
+	// the stop may have failed because the context is done, the leftover is recorded anyway
+	ctx = context.WithoutCancel(ctx)
+	persistedState, err := statePersister.GetState(ctx, executionId)
+	if err != nil {
+		log.Debug().Err(err).Str("executionId", executionId.String()).Msg("state cannot be loaded, cannot update leftover")
+		return
+	}
+	if persistedState.Leftover == nil && leftover == nil {
+		return
+	}
+	updated := *persistedState
+	updated.Leftover = leftover
+	if err := statePersister.PersistState(ctx, &updated); err != nil {
+		log.Warn().Err(err).Str("executionId", executionId.String()).Msg("failed persisting leftover")
+	}
+}
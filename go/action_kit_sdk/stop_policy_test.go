@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package action_kit_sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk/state_persister"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallStop_retries_until_success(t *testing.T) {
+	useInmemoryStatePersister(t)
+	executionId := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: "action"}))
+	markAsLeftover(context.Background(), executionId, "previous attempt", errors.New("boom"))
+	require.NotNil(t, getLeftover(context.Background(), executionId))
+
+	attempts := 0
+	policy := StopPolicy{AttemptTimeout: time.Second, MaxAttempts: 3, Backoff: time.Millisecond}
+	result, err := callStop(context.Background(), policy, executionId, "action", "test", func(ctx context.Context) (*action_kit_api.StopResult, error) {
+		attempts++
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline, "each attempt must have a deadline")
+		if attempts < 2 {
+			return nil, errors.New("not yet")
+		}
+		return &action_kit_api.StopResult{}, nil
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 2, attempts)
+	assert.Nil(t, getLeftover(context.Background(), executionId), "a successful stop clears the leftover")
+}
+
+func TestCallStop_records_leftover(t *testing.T) {
+	useInmemoryStatePersister(t)
+	executionId := uuid.New()
+	require.NoError(t, statePersister.PersistState(context.Background(), &state_persister.PersistedState{ExecutionId: executionId, ActionId: "action"}))
+	attempts := 0
+	policy := StopPolicy{MaxAttempts: 2, Backoff: time.Millisecond}
+	_, err := callStop(context.Background(), policy, executionId, "action", "heartbeat timeout", func(ctx context.Context) (*action_kit_api.StopResult, error) {
+		attempts++
+		return nil, errors.New("tc failed")
+	})
+
+	assert.EqualError(t, err, "tc failed")
+	assert.Equal(t, 2, attempts)
+	leftover := getLeftover(context.Background(), executionId)
+	require.NotNil(t, leftover)
+	assert.Equal(t, executionId, leftover.ExecutionId)
+	assert.Equal(t, "action", leftover.ActionId)
+	assert.Equal(t, "heartbeat timeout", leftover.Reason)
+	assert.Equal(t, "Reverting the action failed, manual cleanup required: tc failed", leftover.message())
+	assert.Equal(t, []Leftover{*leftover}, GetLeftovers())
+
+	persistedState, err := statePersister.GetState(context.Background(), executionId)
+	require.NoError(t, err)
+	assert.Equal(t, "tc failed", persistedState.Leftover.Error, "the leftover is kept in the persisted state")
+}
+
+func TestDefaultStopPolicy_calls_stop_once_without_deadline(t *testing.T) {
+	useInmemoryStatePersister(t)
+	attempts := 0
+	_, err := callStop(context.Background(), DefaultStopPolicy, uuid.New(), "action", "test", func(ctx context.Context) (*action_kit_api.StopResult, error) {
+		attempts++
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		return nil, errors.New("failed")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestCallStop_stops_retrying_when_context_is_done(t *testing.T) {
+	useInmemoryStatePersister(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	policy := StopPolicy{MaxAttempts: 5, Backoff: time.Hour}
+	_, err := callStop(ctx, policy, uuid.New(), "action", "test", func(ctx context.Context) (*action_kit_api.StopResult, error) {
+		attempts++
+		cancel()
+		return nil, errors.New("failed")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestStopPolicy_deadline(t *testing.T) {
+	assert.Zero(t, DefaultStopPolicy.deadline(), "no deadline without attempt timeout")
+	assert.Equal(t, time.Second, StopPolicy{AttemptTimeout: time.Second}.deadline())
+	assert.Equal(t, 3*time.Second+300*time.Millisecond, StopPolicy{AttemptTimeout: time.Second, MaxAttempts: 3, Backoff: 100 * time.Millisecond}.deadline())
+}